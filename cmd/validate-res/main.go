@@ -0,0 +1,137 @@
+// Checks every resource file the game loads at startup, so asset changes can be verified before committing.
+// Run from the repository root: go run ./cmd/validate-res
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Jack-Craig/gogame/src/common"
+	"github.com/Jack-Craig/gogame/src/graphics"
+	"github.com/Jack-Craig/gogame/src/tiled"
+)
+
+func main() {
+	resDir := flag.String("res", "res", "path to the resource directory")
+	flag.Parse()
+
+	var errs []error
+	check := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	spritePath := filepath.Join(*resDir, "spritesheet.json")
+	check(graphics.ValidateSpriteMap(spritePath))
+
+	movementPath := filepath.Join(*resDir, "movement.json")
+	var movement common.MovementJson
+	check(common.LoadJSON(movementPath, &movement))
+
+	biomePath := filepath.Join(*resDir, "world", "biomes.json")
+	var biomes common.BiomeDataJson
+	if err := common.LoadJSON(biomePath, &biomes); err != nil {
+		check(err)
+	} else {
+		for _, name := range common.SortedKeys(biomes.Biomes) {
+			b := biomes.Biomes[name]
+			check(checkSprite(biomePath, fmt.Sprintf("biomes.%s.surfaceTile", name), b.SurfaceTile))
+			check(checkSprite(biomePath, fmt.Sprintf("biomes.%s.subsurfaceTile", name), b.SubsurfaceTile))
+			check(checkSprite(biomePath, fmt.Sprintf("biomes.%s.climbTile", name), b.ClimbTile))
+			if _, ok := movement.Materials[b.Material]; b.Material != "" && !ok {
+				check(fmt.Errorf("%s: biomes.%s.material: unknown material %q", biomePath, name, b.Material))
+			}
+			for i, layer := range b.BackgroundLayers {
+				check(checkSprite(biomePath, fmt.Sprintf("biomes.%s.backgroundLayers[%d]", name, i), layer))
+			}
+			for _, prefab := range common.SortedKeys(b.Prefabs) {
+				if _, err := os.Stat(filepath.Join(*resDir, "world", "chunks", prefab+".json")); err != nil {
+					check(fmt.Errorf("%s: biomes.%s.prefabs.%s: %w", biomePath, name, prefab, err))
+				}
+			}
+		}
+	}
+
+	chunkPaths, _ := filepath.Glob(filepath.Join(*resDir, "world", "chunks", "*.json"))
+	for _, chunkPath := range chunkPaths {
+		var prefab common.PrefabJson
+		check(common.LoadJSON(chunkPath, &prefab))
+	}
+
+	pickupPath := filepath.Join(*resDir, "pickups.json")
+	var pickups common.PickupDataJson
+	if err := common.LoadJSON(pickupPath, &pickups); err != nil {
+		check(err)
+	} else {
+		for _, name := range common.SortedKeys(pickups.Pickups) {
+			check(checkSprite(pickupPath, fmt.Sprintf("pickups.%s.sprite", name), pickups.Pickups[name].Sprite))
+		}
+	}
+
+	arenaPath := filepath.Join(*resDir, "arena.json")
+	var arena common.ArenaJson
+	if err := common.LoadJSON(arenaPath, &arena); err != nil {
+		check(err)
+	} else {
+		if _, ok := biomes.Biomes[arena.Biome]; !ok {
+			check(fmt.Errorf("%s: biome: unknown biome %q", arenaPath, arena.Biome))
+		}
+		if _, ok := pickups.LootTables[arena.WeaponLootTable]; !ok {
+			check(fmt.Errorf("%s: weaponLootTable: unknown loot table %q", arenaPath, arena.WeaponLootTable))
+		}
+	}
+
+	// Tiles in Tiled maps may name their sprite, and pickup objects name an item from pickups.json or leave it out
+	// for a random one
+	mapPaths, _ := filepath.Glob(filepath.Join(*resDir, "maps", "*.tmx"))
+	for _, mapPath := range mapPaths {
+		m, err := tiled.Load(mapPath)
+		if err != nil {
+			check(err)
+			continue
+		}
+		for _, ts := range m.Tilesets {
+			for _, tile := range ts.Tiles {
+				if tile.Properties.Has("sprite") {
+					check(checkSprite(mapPath, fmt.Sprintf("tileset %q: tile %d", ts.Name, tile.ID), tile.Properties.Int("sprite", 0)))
+				}
+			}
+		}
+		for _, group := range m.ObjectGroups {
+			for _, obj := range group.Objects {
+				item := obj.Properties.String("item", "")
+				if _, ok := pickups.Pickups[item]; obj.Kind() == "pickup" && item != "" && !ok {
+					check(fmt.Errorf("%s: object %d: unknown item %q", mapPath, obj.ID, item))
+				}
+			}
+		}
+	}
+
+	modelPath := filepath.Join(*resDir, "models.json")
+	var models common.PlayerDataJson
+	if err := common.LoadJSON(modelPath, &models); err != nil {
+		check(err)
+	} else {
+		for _, name := range common.SortedKeys(models.Players) {
+			check(checkSprite(modelPath, fmt.Sprintf("players.%s.imageId", name), models.Players[name].ImageId))
+		}
+	}
+
+	if len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(1)
+	}
+	fmt.Println("All resource files are valid")
+}
+
+func checkSprite(filePath, field string, id int) error {
+	if id < 0 || id >= int(graphics.Final) {
+		return fmt.Errorf("%s: %s: sprite %d does not exist", filePath, field, id)
+	}
+	return nil
+}
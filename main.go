@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"image/color"
 	"log"
 	"math/rand"
@@ -42,11 +43,17 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeigh
 }
 
 func main() {
+	mapPath := flag.String("map", "", "Tiled .tmx map to play instead of a generated level")
+	seed := flag.Int64("seed", 0, "Seed for generated levels, random if 0")
+	flag.Parse()
 	rand.Seed(time.Now().Unix())
+	if *seed == 0 {
+		*seed = rand.Int63()
+	}
 	ebiten.SetFullscreen(false)
 	ebiten.SetWindowSize(940, 720)
 	ebiten.SetWindowTitle("Hello, World!")
-	if err := ebiten.RunGame(&Game{currentState: gameplay.NewMenuState()}); err != nil {
+	if err := ebiten.RunGame(&Game{currentState: gameplay.NewMenuState(*mapPath, *seed)}); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,36 @@
+<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" tiledversion="1.10.2" name="tiles" tilewidth="16" tileheight="16" tilecount="5" columns="5">
+ <image source="tiles.png" width="80" height="16"/>
+ <tile id="0">
+  <properties>
+   <property name="sprite" type="int" value="0"/>
+   <property name="health" type="float" value="75"/>
+  </properties>
+ </tile>
+ <tile id="1">
+  <properties>
+   <property name="sprite" type="int" value="1"/>
+   <property name="health" type="float" value="75"/>
+  </properties>
+ </tile>
+ <tile id="2">
+  <properties>
+   <property name="sprite" type="int" value="2"/>
+   <property name="health" type="float" value="200"/>
+  </properties>
+ </tile>
+ <tile id="3">
+  <properties>
+   <property name="sprite" type="int" value="22"/>
+   <property name="passable" type="bool" value="true"/>
+   <property name="climbable" type="bool" value="true"/>
+  </properties>
+ </tile>
+ <tile id="4">
+  <properties>
+   <property name="sprite" type="int" value="23"/>
+   <property name="passable" type="bool" value="true"/>
+   <property name="climbable" type="bool" value="true"/>
+  </properties>
+ </tile>
+</tileset>
@@ -2,8 +2,8 @@ package common
 
 import (
 	"encoding/json"
-	"io/ioutil"
-	"log"
+	"errors"
+	"fmt"
 	"math"
 	"os"
 )
@@ -14,34 +14,197 @@ type Pair struct {
 }
 
 type BiomeJson struct {
-	NextTo          []string `json:"nextTo"`
-	SurfaceTiles    int      `json:"surfaceTiles"`
-	SubSurfaceTiles int      `json:"subsurfaceTiles"`
-	GenAmplitude    uint32   `json:"genAmplitude"`
-	GenFrequency    float64  `json:"genFrequency"`
+	// Biomes that can follow this one, and their relative weights
+	NextTo         map[string]float64 `json:"nextTo"`
+	MinLength      uint32             `json:"minLength"`
+	MaxLength      uint32             `json:"maxLength"`
+	SurfaceTile    int                `json:"surfaceTile"`
+	SubsurfaceTile int                `json:"subsurfaceTile"`
+	ClimbTile      int                `json:"climbTile"`
+	TileHealth     float32            `json:"tileHealth"`
+	// Key into MovementJson.Materials for surface and subsurface tiles, "default" if empty
+	Material     string  `json:"material"`
+	GenAmplitude uint32  `json:"genAmplitude"`
+	GenFrequency float64 `json:"genFrequency"`
+	// Sprite ids of the far, middle and near background layers
+	BackgroundLayers [3]int         `json:"backgroundLayers"`
+	SkyColor         [3]uint8       `json:"skyColor"`
+	Generation       GenerationJson `json:"generation"`
+	// Chance of each hand made chunk being placed after this biome, rolled in name order
+	Prefabs map[string]float64 `json:"prefabs"`
+}
+
+// Characters used in PrefabJson tile rows
+const (
+	PrefabAir        = '.'
+	PrefabSubsurface = '#'
+	PrefabSurface    = '='
+	PrefabClimbable  = 'H'
+	PrefabZombie     = 'Z'
+	PrefabPickup     = 'P'
+)
+
+// Hand made chunk of level, placed between biomes
+type PrefabJson struct {
+	// Rows of tiles from top to bottom. Everything below the last row is filled in solid
+	Tiles []string `json:"tiles"`
+	// Row of the floor at the left and right edges, lined up with the surrounding terrain
+	LeftFloor  int `json:"leftFloor"`
+	RightFloor int `json:"rightFloor"`
+}
+
+// Per biome settings for each world generation pass. Zero values turn a pass off
+type GenerationJson struct {
+	Caves struct {
+		Enabled   bool    `json:"enabled"`
+		Frequency float64 `json:"frequency"`
+		Threshold float64 `json:"threshold"`
+		MinDepth  uint32  `json:"minDepth"`
+	} `json:"caves"`
+	Platforms struct {
+		Chance    float64 `json:"chance"`
+		MinLength uint32  `json:"minLength"`
+		MaxLength uint32  `json:"maxLength"`
+		MinHeight uint32  `json:"minHeight"`
+		MaxHeight uint32  `json:"maxHeight"`
+	} `json:"platforms"`
+	Overhangs struct {
+		Chance    float64 `json:"chance"`
+		MaxLength uint32  `json:"maxLength"`
+	} `json:"overhangs"`
+	Decoration struct {
+		VineChance float64 `json:"vineChance"`
+	} `json:"decoration"`
 }
 
 type BiomeDataJson struct {
 	Biomes map[string]BiomeJson `json:"biomes"`
 }
 
+// Tunables for how players move, speeds in pixels per tick and times in milliseconds
+type MovementJson struct {
+	MaxRunSpeed float32 `json:"maxRunSpeed"`
+	// Change in horizontal speed per tick when speeding up to, or slowing down from, a run
+	GroundAccel float32 `json:"groundAccel"`
+	GroundDecel float32 `json:"groundDecel"`
+	AirAccel    float32 `json:"airAccel"`
+	AirDecel    float32 `json:"airDecel"`
+	JumpSpeed   float32 `json:"jumpSpeed"`
+	// Upward speed is multiplied by this when jump is let go early, for short hops
+	JumpReleaseMultiplier float32 `json:"jumpReleaseMultiplier"`
+	// Jumps still work this long after walking off a ledge
+	CoyoteTimeMs int64 `json:"coyoteTimeMs"`
+	// Jumps pressed this long before landing happen on landing
+	JumpBufferMs int64                   `json:"jumpBufferMs"`
+	Materials    map[string]MaterialJson `json:"materials"`
+}
+
+// How a tile feels to walk on
+type MaterialJson struct {
+	// Scales ground acceleration and deceleration, low is slippery
+	Friction float32 `json:"friction"`
+	// Scales top running speed
+	SpeedMultiplier float32 `json:"speedMultiplier"`
+}
+
+// What a pickup does to the player that collects it
+const (
+	EffectHeal     = "heal"
+	EffectAmmo     = "ammo"
+	EffectCoins    = "coins"
+	EffectDamage   = "damage"
+	EffectFireRate = "fireRate"
+	EffectShield   = "shield"
+)
+
+type PickupDataJson struct {
+	Pickups map[string]PickupJson `json:"pickups"`
+	// Zombies drop from the table named after their archetype, and "terrain" places pickups in generated levels
+	LootTables map[string]LootTableJson `json:"lootTables"`
+}
+
+type PickupJson struct {
+	Sprite int    `json:"sprite"`
+	Effect string `json:"effect"`
+	// Health healed, rockets or coins given, or how much damage and fire rate are multiplied by
+	Amount float32 `json:"amount"`
+	// Damage, fire rate and shield power ups wear off after this long
+	DurationMs int64 `json:"durationMs"`
+}
+
+type LootTableJson struct {
+	// Chance of dropping anything at all, per kill or per generated column
+	DropChance float64 `json:"dropChance"`
+	// Relative weight of each pickup, by name
+	Items map[string]float64 `json:"items"`
+}
+
+// Characters used in ArenaJson tile rows, along with the prefab air, ground and climbable tiles
+const (
+	ArenaPlayerSpawn = 'S'
+	ArenaWeaponSpawn = 'W'
+)
+
+// Single screen map for versus matches
+type ArenaJson struct {
+	// Rows of tiles from top to bottom, every tile is unbreakable
+	Tiles []string `json:"tiles"`
+	// Biome the tiles and sky are taken from
+	Biome string `json:"biome"`
+	// A round ends once a team scores this many points or the time runs out, 0 for no limit
+	ScoreLimit  int   `json:"scoreLimit"`
+	TimeLimitMs int64 `json:"timeLimitMs"`
+	// Dead players come back after this long. With 0 they stay dead and the round ends when one team is left,
+	// each of its survivors scoring LastStandingPoints
+	RespawnMs          int64 `json:"respawnMs"`
+	LastStandingPoints int   `json:"lastStandingPoints"`
+	// Weapon spawn points roll from this loot table, and restock this long after being picked up
+	WeaponLootTable string `json:"weaponLootTable"`
+	WeaponRespawnMs int64  `json:"weaponRespawnMs"`
+}
+
 type PlayerDataJson struct {
 	Players map[string]struct {
 		ImageId int `json:"imageId"`
 	} `json:"players"`
 }
 
-func LoadJSON[T any](filePath string, container T) {
+// Resource files implement Validator to be checked after they are decoded
+type Validator interface {
+	Validate() error
+}
+
+// Strictly decodes the JSON file at filePath into container. Unknown fields are rejected,
+// and container is validated afterwards if it implements Validator
+func LoadJSON[T any](filePath string, container T) error {
 	jsonFile, err := os.Open(filePath)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	defer jsonFile.Close()
-	jsonFileBytes, _ := ioutil.ReadAll(jsonFile)
-	if err != nil {
-		log.Fatal(err)
+	decoder := json.NewDecoder(jsonFile)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(container); err != nil {
+		return fmt.Errorf("%s: %w", filePath, describeJSONError(err))
+	}
+	if v, ok := any(container).(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", filePath, err)
+		}
+	}
+	return nil
+}
+
+func describeJSONError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Errorf("field %s: expected %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Errorf("offset %d: %w", syntaxErr.Offset, err)
 	}
-	json.Unmarshal(jsonFileBytes, container)
+	return err
 }
 
 func Remove[T any](slice *[]T, index int) []T {
@@ -87,74 +250,42 @@ func Normalize(v1 Vec2) Vec2 {
 	return NewVec2(v1.X/magn, v1.Y/magn)
 }
 
-func MinMaxProjection(x, y, width, height float64, axis Vec2) (float64, float64) {
-	// TL
-	projection := Dot(NewVec2(x, y), axis)
-	min := projection
-	max := projection
-
-	// TR
-	projection = Dot(NewVec2(x+width, y), axis)
-	min = math.Min(min, projection)
-	max = math.Max(max, projection)
-
-	// BR
-	projection = Dot(NewVec2(x+width, y+height), axis)
-	min = math.Min(min, projection)
-	max = math.Max(max, projection)
-
-	// BL
-	projection = Dot(NewVec2(x, y+height), axis)
-	min = math.Min(min, projection)
-	max = math.Max(max, projection)
+// Corners of a width by height box rotated by theta about its top left x, y. Clockwise from the top left
+func BoxCorners(x, y, width, height, theta float64) [4]Vec2 {
+	right, down := BoxAxes(theta)
+	tl := NewVec2(x, y)
+	tr := Add(tl, Scale(right, width))
+	br := Add(tr, Scale(down, height))
+	bl := Add(tl, Scale(down, height))
+	return [4]Vec2{tl, tr, br, bl}
+}
 
-	return min, max
+// Unit vectors along the top and left edges of a box rotated by theta, which are also its edge normals
+func BoxAxes(theta float64) (Vec2, Vec2) {
+	cos, sin := math.Cos(theta), math.Sin(theta)
+	return NewVec2(cos, sin), NewVec2(-sin, cos)
 }
 
-func TwoMaxPoints(p1, p2, p3, p4 float64) (float64, float64) {
-	// m1 is max1, m2 is max2
-	if p1 > p2 && p1 > p3 && p1 > p4 {
-		return p1, MaxPoint(p2, p3, p4)
-	}
-	if p2 > p1 && p2 > p3 && p2 > p4 {
-		return p2, MaxPoint(p1, p3, p4)
+// Smallest and largest projection of points onto axis
+func ProjectPoints(points []Vec2, axis Vec2) (float64, float64) {
+	min := Dot(points[0], axis)
+	max := min
+	for _, p := range points[1:] {
+		projection := Dot(p, axis)
+		min = math.Min(min, projection)
+		max = math.Max(max, projection)
 	}
-	if p3 > p2 && p3 > p1 && p3 > p4 {
-		return p1, MaxPoint(p1, p2, p4)
-	}
-	return p4, MaxPoint(p1, p2, p3)
+	return min, max
 }
 
-func MaxPoint(p1, p2, p3 float64) float64 {
-	if p1 > p2 && p1 > p3 {
-		return p1
-	}
-	if p2 > p1 && p2 > p3 {
-		return p2
-	}
-	return p3
+func Scale(v1 Vec2, s float64) Vec2 {
+	return NewVec2(v1.X*s, v1.Y*s)
 }
 
-func TwoMinPoints(p1, p2, p3, p4 float64) (float64, float64) {
-	// m1 is max1, m2 is max2
-	if p1 < p2 && p1 < p3 && p1 < p4 {
-		return p1, MaxPoint(p2, p3, p4)
-	}
-	if p2 < p1 && p2 < p3 && p2 < p4 {
-		return p2, MaxPoint(p1, p3, p4)
-	}
-	if p3 < p2 && p3 < p1 && p3 < p4 {
-		return p1, MaxPoint(p1, p2, p4)
-	}
-	return p4, MaxPoint(p1, p2, p3)
+func MaxPoint(p1, p2, p3 float64) float64 {
+	return math.Max(p1, math.Max(p2, p3))
 }
 
 func MinPoint(p1, p2, p3 float64) float64 {
-	if p1 < p2 && p1 < p3 {
-		return p1
-	}
-	if p2 < p1 && p2 < p3 {
-		return p2
-	}
-	return p3
+	return math.Min(p1, math.Min(p2, p3))
 }
@@ -0,0 +1,94 @@
+package common
+
+import (
+	"math"
+	"testing"
+)
+
+const epsilon = 1e-9
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < epsilon
+}
+
+func nearVec(a, b Vec2) bool {
+	return near(a.X, b.X) && near(a.Y, b.Y)
+}
+
+func TestBoxAxes(t *testing.T) {
+	r := math.Sqrt2 / 2
+	tests := []struct {
+		name        string
+		theta       float64
+		right, down Vec2
+	}{
+		{"zero", 0, NewVec2(1, 0), NewVec2(0, 1)},
+		{"quarter turn", math.Pi / 2, NewVec2(0, 1), NewVec2(-1, 0)},
+		{"eighth turn", math.Pi / 4, NewVec2(r, r), NewVec2(-r, r)},
+		{"negative eighth turn", -math.Pi / 4, NewVec2(r, -r), NewVec2(r, r)},
+		{"negative quarter turn", -math.Pi / 2, NewVec2(0, -1), NewVec2(1, 0)},
+	}
+	for _, tt := range tests {
+		right, down := BoxAxes(tt.theta)
+		if !nearVec(right, tt.right) || !nearVec(down, tt.down) {
+			t.Errorf("%s: got %v, %v, expected %v, %v", tt.name, right, down, tt.right, tt.down)
+		}
+		if !near(Dot(right, down), 0) {
+			t.Errorf("%s: axes %v and %v are not perpendicular", tt.name, right, down)
+		}
+	}
+}
+
+func TestBoxCorners(t *testing.T) {
+	r := math.Sqrt2 / 2
+	tests := []struct {
+		name                string
+		x, y, width, height float64
+		theta               float64
+		corners             [4]Vec2
+	}{
+		{"zero", 10, 20, 4, 2, 0,
+			[4]Vec2{NewVec2(10, 20), NewVec2(14, 20), NewVec2(14, 22), NewVec2(10, 22)}},
+		{"quarter turn", 10, 20, 4, 2, math.Pi / 2,
+			[4]Vec2{NewVec2(10, 20), NewVec2(10, 24), NewVec2(8, 24), NewVec2(8, 20)}},
+		{"eighth turn", 0, 0, 2, 2, math.Pi / 4,
+			[4]Vec2{NewVec2(0, 0), NewVec2(2*r, 2*r), NewVec2(0, 4*r), NewVec2(-2*r, 2*r)}},
+		{"negative quarter turn", 10, 20, 4, 2, -math.Pi / 2,
+			[4]Vec2{NewVec2(10, 20), NewVec2(10, 16), NewVec2(12, 16), NewVec2(12, 20)}},
+	}
+	for _, tt := range tests {
+		corners := BoxCorners(tt.x, tt.y, tt.width, tt.height, tt.theta)
+		for i := range corners {
+			if !nearVec(corners[i], tt.corners[i]) {
+				t.Errorf("%s: corner %d is %v, expected %v", tt.name, i, corners[i], tt.corners[i])
+			}
+		}
+	}
+}
+
+func TestProjectPoints(t *testing.T) {
+	r := math.Sqrt2 / 2
+	square := BoxCorners(0, 0, 2, 2, 0)
+	diamond := BoxCorners(0, 0, 2, 2, math.Pi/4)
+	tests := []struct {
+		name     string
+		points   []Vec2
+		axis     Vec2
+		min, max float64
+	}{
+		// Two corners share each end of an axis aligned box
+		{"ties on x", square[:], NewVec2(1, 0), 0, 2},
+		{"ties on y", square[:], NewVec2(0, 1), 0, 2},
+		{"diagonal", square[:], NewVec2(r, r), 0, 4 * r},
+		{"negative axis", square[:], NewVec2(-1, 0), -2, 0},
+		{"rotated box", diamond[:], NewVec2(1, 0), -2 * r, 2 * r},
+		{"single point", []Vec2{NewVec2(3, 4)}, NewVec2(0, 1), 4, 4},
+		{"all equal", []Vec2{NewVec2(1, 1), NewVec2(1, 1), NewVec2(1, 1)}, NewVec2(1, 0), 1, 1},
+	}
+	for _, tt := range tests {
+		min, max := ProjectPoints(tt.points, tt.axis)
+		if !near(min, tt.min) || !near(max, tt.max) {
+			t.Errorf("%s: got %v, %v, expected %v, %v", tt.name, min, max, tt.min, tt.max)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Map keys in a stable order, for deterministic iteration and error messages
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (bd *BiomeDataJson) Validate() error {
+	if len(bd.Biomes) == 0 {
+		return errors.New("biomes: no biomes defined")
+	}
+	var errs []error
+	if _, ok := bd.Biomes["start"]; !ok {
+		errs = append(errs, errors.New("biomes.start: missing, every level begins with it"))
+	}
+	for _, name := range SortedKeys(bd.Biomes) {
+		b := bd.Biomes[name]
+		field := func(f string) string {
+			return fmt.Sprintf("biomes.%s.%s", name, f)
+		}
+		if len(b.NextTo) == 0 {
+			errs = append(errs, fmt.Errorf("%s: must list at least one biome", field("nextTo")))
+		}
+		for _, next := range SortedKeys(b.NextTo) {
+			if _, ok := bd.Biomes[next]; !ok {
+				errs = append(errs, fmt.Errorf("%s: unknown biome %q", field("nextTo"), next))
+			}
+			if b.NextTo[next] <= 0 {
+				errs = append(errs, fmt.Errorf("%s.%s: weight must be greater than 0", field("nextTo"), next))
+			}
+		}
+		if b.MinLength == 0 {
+			errs = append(errs, fmt.Errorf("%s: must be greater than 0", field("minLength")))
+		}
+		if b.MinLength > b.MaxLength {
+			errs = append(errs, fmt.Errorf("%s: greater than maxLength", field("minLength")))
+		}
+		if b.SurfaceTile < 0 || b.SubsurfaceTile < 0 || b.ClimbTile < 0 {
+			errs = append(errs, fmt.Errorf("%s: tile ids must not be negative", field("surfaceTile")))
+		}
+		if b.TileHealth < 0 {
+			errs = append(errs, fmt.Errorf("%s: must not be negative", field("tileHealth")))
+		}
+		if b.GenFrequency <= 0 {
+			errs = append(errs, fmt.Errorf("%s: must be greater than 0", field("genFrequency")))
+		}
+		errs = append(errs, b.Generation.validate(field("generation"))...)
+		for _, prefab := range SortedKeys(b.Prefabs) {
+			if chance := b.Prefabs[prefab]; chance < 0 || chance > 1 {
+				errs = append(errs, fmt.Errorf("%s.%s: must be between 0 and 1", field("prefabs"), prefab))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (g *GenerationJson) validate(prefix string) []error {
+	var errs []error
+	checkChance := func(f string, chance float64) {
+		if chance < 0 || chance > 1 {
+			errs = append(errs, fmt.Errorf("%s.%s: must be between 0 and 1", prefix, f))
+		}
+	}
+	if g.Caves.Enabled && g.Caves.Frequency <= 0 {
+		errs = append(errs, fmt.Errorf("%s.caves.frequency: must be greater than 0", prefix))
+	}
+	// Perlin noise stays within -1 and 1, so a threshold outside of that carves everything or nothing
+	if g.Caves.Enabled && (g.Caves.Threshold <= -1 || g.Caves.Threshold >= 1) {
+		errs = append(errs, fmt.Errorf("%s.caves.threshold: must be between -1 and 1", prefix))
+	}
+	checkChance("platforms.chance", g.Platforms.Chance)
+	if g.Platforms.Chance > 0 && g.Platforms.MinLength == 0 {
+		errs = append(errs, fmt.Errorf("%s.platforms.minLength: must be greater than 0 when platforms.chance is", prefix))
+	}
+	if g.Platforms.Chance > 0 && g.Platforms.MaxHeight == 0 {
+		errs = append(errs, fmt.Errorf("%s.platforms.maxHeight: must be greater than 0 when platforms.chance is", prefix))
+	}
+	if g.Platforms.MinLength > g.Platforms.MaxLength {
+		errs = append(errs, fmt.Errorf("%s.platforms.minLength: greater than maxLength", prefix))
+	}
+	if g.Platforms.MinHeight > g.Platforms.MaxHeight {
+		errs = append(errs, fmt.Errorf("%s.platforms.minHeight: greater than maxHeight", prefix))
+	}
+	checkChance("overhangs.chance", g.Overhangs.Chance)
+	if g.Overhangs.Chance > 0 && g.Overhangs.MaxLength == 0 {
+		errs = append(errs, fmt.Errorf("%s.overhangs.maxLength: must be greater than 0 when overhangs.chance is", prefix))
+	}
+	checkChance("decoration.vineChance", g.Decoration.VineChance)
+	return errs
+}
+
+func (m *MovementJson) Validate() error {
+	var errs []error
+	positive := func(field string, v float32) {
+		if v <= 0 {
+			errs = append(errs, fmt.Errorf("%s: must be greater than 0", field))
+		}
+	}
+	positive("maxRunSpeed", m.MaxRunSpeed)
+	positive("groundAccel", m.GroundAccel)
+	positive("groundDecel", m.GroundDecel)
+	positive("airAccel", m.AirAccel)
+	positive("airDecel", m.AirDecel)
+	positive("jumpSpeed", m.JumpSpeed)
+	if m.JumpReleaseMultiplier < 0 || m.JumpReleaseMultiplier > 1 {
+		errs = append(errs, errors.New("jumpReleaseMultiplier: must be between 0 and 1"))
+	}
+	if m.CoyoteTimeMs < 0 {
+		errs = append(errs, errors.New("coyoteTimeMs: must not be negative"))
+	}
+	if m.JumpBufferMs < 0 {
+		errs = append(errs, errors.New("jumpBufferMs: must not be negative"))
+	}
+	if _, ok := m.Materials["default"]; !ok {
+		errs = append(errs, errors.New("materials: no default material"))
+	}
+	for _, name := range SortedKeys(m.Materials) {
+		positive(fmt.Sprintf("materials.%s.friction", name), m.Materials[name].Friction)
+		positive(fmt.Sprintf("materials.%s.speedMultiplier", name), m.Materials[name].SpeedMultiplier)
+	}
+	return errors.Join(errs...)
+}
+
+func (pd *PickupDataJson) Validate() error {
+	var errs []error
+	for _, name := range SortedKeys(pd.Pickups) {
+		p := pd.Pickups[name]
+		field := func(f string) string {
+			return fmt.Sprintf("pickups.%s.%s", name, f)
+		}
+		switch p.Effect {
+		case EffectHeal, EffectAmmo, EffectCoins:
+			if p.Amount <= 0 {
+				errs = append(errs, fmt.Errorf("%s: must be greater than 0", field("amount")))
+			}
+		case EffectDamage, EffectFireRate:
+			if p.Amount <= 0 {
+				errs = append(errs, fmt.Errorf("%s: must be greater than 0", field("amount")))
+			}
+			fallthrough
+		case EffectShield:
+			if p.DurationMs <= 0 {
+				errs = append(errs, fmt.Errorf("%s: must be greater than 0", field("durationMs")))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("%s: unknown effect %q", field("effect"), p.Effect))
+		}
+	}
+	for _, name := range SortedKeys(pd.LootTables) {
+		lt := pd.LootTables[name]
+		if lt.DropChance < 0 || lt.DropChance > 1 {
+			errs = append(errs, fmt.Errorf("lootTables.%s.dropChance: must be between 0 and 1", name))
+		}
+		if len(lt.Items) == 0 {
+			errs = append(errs, fmt.Errorf("lootTables.%s.items: must list at least one pickup", name))
+		}
+		for _, item := range SortedKeys(lt.Items) {
+			if _, ok := pd.Pickups[item]; !ok {
+				errs = append(errs, fmt.Errorf("lootTables.%s.items: unknown pickup %q", name, item))
+			}
+			if lt.Items[item] <= 0 {
+				errs = append(errs, fmt.Errorf("lootTables.%s.items.%s: weight must be greater than 0", name, item))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (pd *PlayerDataJson) Validate() error {
+	if len(pd.Players) == 0 {
+		return errors.New("players: no players defined")
+	}
+	var errs []error
+	for _, name := range SortedKeys(pd.Players) {
+		if pd.Players[name].ImageId < 0 {
+			errs = append(errs, fmt.Errorf("players.%s.imageId: must not be negative", name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *PrefabJson) Validate() error {
+	if len(p.Tiles) == 0 || len(p.Tiles[0]) == 0 {
+		return errors.New("tiles: must have at least one row and column")
+	}
+	var errs []error
+	width := len(p.Tiles[0])
+	for i, row := range p.Tiles {
+		if len(row) != width {
+			errs = append(errs, fmt.Errorf("tiles[%d]: %d wide, expected %d", i, len(row), width))
+		}
+		for j, c := range row {
+			if !isPrefabTile(c) {
+				errs = append(errs, fmt.Errorf("tiles[%d][%d]: unknown tile %q", i, j, c))
+			}
+		}
+	}
+	isSolid := func(row, col int) bool {
+		c := p.Tiles[row][col]
+		return c == PrefabSubsurface || c == PrefabSurface
+	}
+	if p.LeftFloor < 0 || p.LeftFloor >= len(p.Tiles) {
+		errs = append(errs, fmt.Errorf("leftFloor: row %d is outside of tiles", p.LeftFloor))
+	} else if !isSolid(p.LeftFloor, 0) {
+		errs = append(errs, fmt.Errorf("leftFloor: tiles[%d][0] is not solid", p.LeftFloor))
+	}
+	if p.RightFloor < 0 || p.RightFloor >= len(p.Tiles) {
+		errs = append(errs, fmt.Errorf("rightFloor: row %d is outside of tiles", p.RightFloor))
+	} else if len(p.Tiles[p.RightFloor]) == width && !isSolid(p.RightFloor, width-1) {
+		errs = append(errs, fmt.Errorf("rightFloor: tiles[%d][%d] is not solid", p.RightFloor, width-1))
+	}
+	return errors.Join(errs...)
+}
+
+func (a *ArenaJson) Validate() error {
+	if len(a.Tiles) == 0 || len(a.Tiles[0]) == 0 {
+		return errors.New("tiles: must have at least one row and column")
+	}
+	var errs []error
+	width := len(a.Tiles[0])
+	spawns := 0
+	for i, row := range a.Tiles {
+		if len(row) != width {
+			errs = append(errs, fmt.Errorf("tiles[%d]: %d wide, expected %d", i, len(row), width))
+		}
+		for j, c := range row {
+			switch c {
+			case PrefabAir, PrefabSubsurface, PrefabSurface, PrefabClimbable, ArenaWeaponSpawn:
+			case ArenaPlayerSpawn:
+				spawns++
+			default:
+				errs = append(errs, fmt.Errorf("tiles[%d][%d]: unknown tile %q", i, j, c))
+			}
+		}
+	}
+	if spawns == 0 {
+		errs = append(errs, fmt.Errorf("tiles: needs at least one player spawn %q", ArenaPlayerSpawn))
+	}
+	if a.Biome == "" {
+		errs = append(errs, errors.New("biome: must be set"))
+	}
+	if a.ScoreLimit < 0 || a.TimeLimitMs < 0 || a.RespawnMs < 0 || a.LastStandingPoints < 0 || a.WeaponRespawnMs < 0 {
+		errs = append(errs, errors.New("scoreLimit, timeLimitMs, respawnMs, lastStandingPoints and weaponRespawnMs: must not be negative"))
+	}
+	if a.ScoreLimit == 0 && a.TimeLimitMs == 0 && a.RespawnMs > 0 {
+		errs = append(errs, errors.New("scoreLimit, timeLimitMs: a round with respawns needs a score limit or a time limit to end"))
+	}
+	return errors.Join(errs...)
+}
+
+func isPrefabTile(c rune) bool {
+	switch c {
+	case PrefabAir, PrefabSubsurface, PrefabSurface, PrefabClimbable, PrefabZombie, PrefabPickup:
+		return true
+	}
+	return false
+}
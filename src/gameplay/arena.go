@@ -0,0 +1,327 @@
+package gameplay
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Jack-Craig/gogame/src/common"
+	"github.com/Jack-Craig/gogame/src/graphics"
+)
+
+const ARENAPATH = "res/arena.json"
+
+// Top left of a spawn tile, in world coordinates
+type spawnPoint struct {
+	x, y float32
+}
+
+// A single screen versus map, see common.ArenaJson for the format
+type ArenaLevel struct {
+	columns                    []Column
+	height                     uint32
+	playerSpawns, weaponSpawns []spawnPoint
+	biome                      common.BiomeJson
+	biomeName                  string
+}
+
+// Builds the arena's tiles from biome, every one unbreakable
+func NewArenaLevel(data common.ArenaJson, biome common.BiomeJson) *ArenaLevel {
+	al := &ArenaLevel{
+		columns:   make([]Column, len(data.Tiles[0])),
+		height:    uint32(len(data.Tiles)),
+		biome:     biome,
+		biomeName: data.Biome,
+	}
+	surface := TileSpec{sprite: graphics.SpriteID(biome.SurfaceTile), hasSprite: true, material: biome.Material}
+	subsurface := TileSpec{sprite: graphics.SpriteID(biome.SubsurfaceTile), hasSprite: true, material: biome.Material}
+	climbable := TileSpec{sprite: graphics.SpriteID(biome.ClimbTile), hasSprite: true, isPassable: true, isClimbable: true}
+	for x := range al.columns {
+		col := airColumn(uint32(x), al.height)
+		for y, row := range data.Tiles {
+			sp := spawnPoint{float32(x) * TILEWIDTH, float32(y) * TILEWIDTH}
+			switch row[x] {
+			case common.PrefabSurface:
+				col.tiles[y] = surface
+			case common.PrefabSubsurface:
+				col.tiles[y] = subsurface
+			case common.PrefabClimbable:
+				col.tiles[y] = climbable
+			case common.ArenaPlayerSpawn:
+				al.playerSpawns = append(al.playerSpawns, sp)
+			case common.ArenaWeaponSpawn:
+				al.weaponSpawns = append(al.weaponSpawns, sp)
+			}
+		}
+		al.columns[x] = col
+	}
+	return al
+}
+
+func (al *ArenaLevel) Column(x uint32) Column {
+	if x < al.Width() {
+		return al.columns[x]
+	}
+	return airColumn(x, al.height)
+}
+
+// Weapons are spawned by the Arena, so they can restock once taken
+func (al *ArenaLevel) Update(worldXStart, worldXEnd uint32) []SpawnEvent {
+	return nil
+}
+
+func (al *ArenaLevel) Width() uint32 {
+	return uint32(len(al.columns))
+}
+
+func (al *ArenaLevel) Height() uint32 {
+	return al.height
+}
+
+func (al *ArenaLevel) PlayerStart() (float32, float32) {
+	return al.playerSpawns[0].x, al.playerSpawns[0].y
+}
+
+// Nobody leaves the arena, rounds end on score or time instead
+func (al *ArenaLevel) IsComplete(worldXEnd uint32, furthestPlayerX float32) bool {
+	return false
+}
+
+func (al *ArenaLevel) BlendAt(worldX float32) (*common.BiomeJson, *common.BiomeJson, float64) {
+	return &al.biome, &al.biome, 1
+}
+
+func (al *ArenaLevel) BiomeAt(worldX float32) string {
+	return al.biomeName
+}
+
+// Scores, respawns and weapon spawns for one versus round
+type Arena struct {
+	w     *World
+	data  common.ArenaJson
+	level *ArenaLevel
+	stats map[*Player]*arenaStats
+	// Weapon spawn points, in the order of level.weaponSpawns
+	weapons []*weaponSpawn
+	// Milliseconds
+	startTime int64
+	// How many teams the round started with, last team standing only counts with more than one
+	numTeams  int
+	roundOver bool
+}
+
+type arenaStats struct {
+	kills, deaths, points int
+	// Milliseconds, when a dead player comes back
+	respawnAt int64
+}
+
+// Restocks with something from the weapon loot table a while after its last one was taken
+type weaponSpawn struct {
+	spawnPoint
+	pickup    *Pickup
+	restockAt int64
+}
+
+// One player's line on the scoreboard
+type arenaResult struct {
+	name                        string
+	team, kills, deaths, points int
+}
+
+func NewArena(w *World, data common.ArenaJson, biome common.BiomeJson) *Arena {
+	a := &Arena{
+		w:         w,
+		data:      data,
+		level:     NewArenaLevel(data, biome),
+		stats:     make(map[*Player]*arenaStats),
+		startTime: time.Now().UnixMilli(),
+	}
+	for _, sp := range a.level.weaponSpawns {
+		a.weapons = append(a.weapons, &weaponSpawn{spawnPoint: sp})
+	}
+	var teams [NUMTEAMS]bool
+	for _, p := range w.players {
+		a.stats[p] = &arenaStats{}
+		if !teams[w.teamOf(p)] {
+			teams[w.teamOf(p)] = true
+			a.numTeams++
+		}
+	}
+	Subscribe(&w.events, func(ev EntityKilled) {
+		victim := w.playerFor(ev.target)
+		if victim == nil || a.roundOver {
+			return
+		}
+		vs := a.stats[victim]
+		vs.deaths++
+		vs.respawnAt = time.Now().UnixMilli() + a.data.RespawnMs
+		switch killer := w.playerFor(ev.source); {
+		case killer == nil || killer == victim:
+			vs.points--
+		case w.sameTeam(killer, victim):
+			a.stats[killer].points--
+		default:
+			a.stats[killer].kills++
+			a.stats[killer].points++
+		}
+	})
+	return a
+}
+
+// Where the ith player starts the round, taking spawn points from alternate ends of the arena so neighbours
+// in the menu, who start on different teams, start apart
+func (a *Arena) startingSpawn(i int) (float32, float32) {
+	spawns := a.level.playerSpawns
+	j := (i / 2) % len(spawns)
+	if i%2 == 1 {
+		j = len(spawns) - 1 - j
+	}
+	return spawns[j].x, spawns[j].y
+}
+
+// The spawn point furthest from the closest enemy of p
+func (a *Arena) respawnPoint(p *Player) spawnPoint {
+	best, bestDist := a.level.playerSpawns[0], -1.0
+	for _, sp := range a.level.playerSpawns {
+		nearest := math.MaxFloat64
+		a.w.inputs.Each(func(id EntityID, e *Player) {
+			if !e.shouldRemove && !a.w.sameTeam(p, e) {
+				nearest = math.Min(nearest, math.Hypot(float64(e.x-sp.x), float64(e.y-sp.y)))
+			}
+		})
+		if nearest > bestDist {
+			best, bestDist = sp, nearest
+		}
+	}
+	return best
+}
+
+func (a *Arena) respawn(p *Player, timeNow int64) {
+	sp := a.respawnPoint(p)
+	a.w.resetPlayer(p, sp.x, sp.y)
+	p.invulnerableUntil = timeNow + ARENASPAWNPROTECTIONMS
+	a.w.AddPlayer(p)
+	a.w.events.Publish(PlayerRespawned{p})
+}
+
+func (a *Arena) spawnWeapon(ws *weaponSpawn) *Pickup {
+	name := rollLoot(a.w.pickupData.LootTables[a.data.WeaponLootTable].Items, nil)
+	data, ok := a.w.pickupData.Pickups[name]
+	if !ok {
+		return nil
+	}
+	pk := NewPickup(name, data, ws.x, ws.y, a.w)
+	a.w.AddPickup(pk)
+	return pk
+}
+
+// Points per team this round
+func (a *Arena) teamScores() [NUMTEAMS]int {
+	var scores [NUMTEAMS]int
+	for p, s := range a.stats {
+		scores[a.w.teamOf(p)] += s.points
+	}
+	return scores
+}
+
+// The team with the most points, or -1 for a draw
+func (a *Arena) winningTeam() int {
+	scores := a.teamScores()
+	winner, best, tied := -1, math.MinInt, false
+	for team, score := range scores {
+		switch {
+		case score > best:
+			winner, best, tied = team, score, false
+		case score == best:
+			tied = true
+		}
+	}
+	if tied {
+		return -1
+	}
+	return winner
+}
+
+// Milliseconds until the time limit, 0 without one
+func (a *Arena) timeLeft(timeNow int64) int64 {
+	if a.data.TimeLimitMs == 0 {
+		return 0
+	}
+	return max(a.startTime+a.data.TimeLimitMs-timeNow, 0)
+}
+
+// Teams with anyone still alive
+func (a *Arena) teamsStanding() int {
+	var standing [NUMTEAMS]bool
+	n := 0
+	for _, p := range a.w.players {
+		if !p.isDead && !standing[a.w.teamOf(p)] {
+			standing[a.w.teamOf(p)] = true
+			n++
+		}
+	}
+	return n
+}
+
+// Every player's stats, best first
+func (a *Arena) results() []arenaResult {
+	var results []arenaResult
+	for _, p := range a.w.players {
+		s := a.stats[p]
+		results = append(results, arenaResult{p.name, a.w.teamOf(p), s.kills, s.deaths, s.points})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].points > results[j].points
+	})
+	return results
+}
+
+// Brings players back, restocks weapons and ends the round
+type ArenaSystem struct{}
+
+func (s *ArenaSystem) Update(w *World) {
+	a := w.arena
+	if a.roundOver {
+		return
+	}
+	timeNow := time.Now().UnixMilli()
+	if a.data.RespawnMs > 0 {
+		for _, p := range w.players {
+			if p.isDead && timeNow >= a.stats[p].respawnAt {
+				a.respawn(p, timeNow)
+			}
+		}
+	}
+	for _, ws := range a.weapons {
+		if ws.pickup != nil {
+			if !ws.pickup.shouldRemove {
+				continue
+			}
+			ws.pickup = nil
+			ws.restockAt = timeNow + a.data.WeaponRespawnMs
+		}
+		if timeNow >= ws.restockAt {
+			ws.pickup = a.spawnWeapon(ws)
+		}
+	}
+
+	if a.data.RespawnMs == 0 && a.numTeams > 1 && a.teamsStanding() <= 1 {
+		for _, p := range w.players {
+			if !p.isDead {
+				a.stats[p].points += a.data.LastStandingPoints
+			}
+		}
+		a.roundOver = true
+	}
+	if a.data.TimeLimitMs > 0 && a.timeLeft(timeNow) == 0 {
+		a.roundOver = true
+	}
+	if a.data.ScoreLimit > 0 {
+		for _, score := range a.teamScores() {
+			if score >= a.data.ScoreLimit {
+				a.roundOver = true
+			}
+		}
+	}
+}
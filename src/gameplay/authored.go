@@ -0,0 +1,238 @@
+package gameplay
+
+import (
+	"fmt"
+	"image/color"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Jack-Craig/gogame/src/common"
+	"github.com/Jack-Craig/gogame/src/graphics"
+	"github.com/Jack-Craig/gogame/src/tiled"
+)
+
+// Object types recognised in a Tiled map's object layers
+const (
+	TiledPlayerStart   = "player_start"
+	TiledExit          = "exit"
+	TiledZombieSpawner = "zombie_spawner"
+	TiledPickup        = "pickup"
+)
+
+// Finds the sprite drawn at a pixel of the spritesheet
+type SpriteLocator interface {
+	GetSpriteAt(x, y int) (graphics.SpriteID, bool)
+}
+
+// A fully authored level made in the Tiled editor
+type AuthoredLevel struct {
+	// Every map column, top aligned to the world
+	columns                    []Column
+	height                     uint32
+	playerStartX, playerStartY float32
+	// In world coordinates, players can leave once one of them passes it
+	exitX    float32
+	hasExit  bool
+	spawners []*zombieSpawner
+	// Sky and background, from the map's background colour
+	biome common.BiomeJson
+	// The whole map is one biome, named after its file
+	biomeName string
+}
+
+// Keeps up to count zombies alive, spawning one every interval while its column is on screen
+type zombieSpawner struct {
+	x, y       float32
+	count      int
+	intervalMs int64
+	lastSpawn  int64
+	alive      int
+}
+
+func (s *zombieSpawner) Despawned() {
+	s.alive--
+}
+
+// Loads a Tiled map. Tile layers are stacked in order, with tile properties "passable", "climbable" and
+// "health" and an optional "sprite" id; tiles without one are matched to spritesheet.json by position. Pickup
+// objects name an item from pickups, or leave it out to roll one from the terrain loot table
+func NewAuthoredLevel(mapPath string, sprites SpriteLocator, pickups map[string]common.PickupJson) (*AuthoredLevel, error) {
+	m, err := tiled.Load(mapPath)
+	if err != nil {
+		return nil, err
+	}
+	al := &AuthoredLevel{
+		columns: make([]Column, m.Width),
+		height:  uint32(m.Height),
+	}
+	for x := range al.columns {
+		al.columns[x] = airColumn(uint32(x), al.height)
+	}
+	for _, layer := range m.Layers {
+		// Whole layers can be made into scenery
+		layerPassable := layer.Properties.Bool("passable", false)
+		for y := 0; y < layer.Height && y < m.Height; y++ {
+			for x := 0; x < layer.Width && x < m.Width; x++ {
+				gid := layer.At(x, y)
+				if gid == 0 {
+					continue
+				}
+				ts, id, ok := m.TilesetFor(gid)
+				if !ok {
+					return nil, fmt.Errorf("%s: layer %q: tile %d at %d,%d has no tileset", mapPath, layer.Name, gid, x, y)
+				}
+				props := ts.TileProperties(id)
+				shape, ok := TileShapeNames[props.String("shape", "full")]
+				if !ok {
+					return nil, fmt.Errorf("%s: tileset %q: tile %d: unknown shape %q", mapPath, ts.Name, id, props.String("shape", ""))
+				}
+				sprite, err := spriteFor(sprites, ts, id, props)
+				if err != nil {
+					return nil, fmt.Errorf("%s: layer %q: %w", mapPath, layer.Name, err)
+				}
+				al.columns[x].tiles[y] = TileSpec{
+					sprite:      sprite,
+					hasSprite:   true,
+					isPassable:  layerPassable || props.Bool("passable", false),
+					isClimbable: props.Bool("climbable", false),
+					health:      float32(props.Float("health", 0)),
+					shape:       shape,
+					material:    props.String("material", ""),
+				}
+			}
+		}
+	}
+	scaleX := float64(TILEWIDTH) / float64(m.TileWidth)
+	scaleY := float64(TILEWIDTH) / float64(m.TileHeight)
+	al.playerStartX, al.playerStartY = PLAYERWORLDSTARTX, PLAYERWORLDSTARTY
+	for _, group := range m.ObjectGroups {
+		for _, obj := range group.Objects {
+			x, y := float32(obj.X*scaleX), float32(obj.Y*scaleY)
+			if obj.GID != 0 {
+				// Tile objects sit on their y
+				y -= float32(obj.Height * scaleY)
+			}
+			switch obj.Kind() {
+			case TiledPlayerStart:
+				al.playerStartX, al.playerStartY = x, y
+			case TiledExit:
+				al.exitX = x
+				al.hasExit = true
+			case TiledZombieSpawner:
+				al.spawners = append(al.spawners, &zombieSpawner{
+					x:          x,
+					y:          y,
+					count:      obj.Properties.Int("count", 3),
+					intervalMs: int64(obj.Properties.Int("interval", 3000)),
+				})
+			case TiledPickup:
+				item := obj.Properties.String("item", "")
+				if _, ok := pickups[item]; item != "" && !ok {
+					return nil, fmt.Errorf("%s: object %d: unknown item %q", mapPath, obj.ID, item)
+				}
+				col := int(x / TILEWIDTH)
+				if col >= 0 && col < m.Width {
+					al.columns[col].spawns = append(al.columns[col].spawns, SpawnEvent{PickupSpawn, x, y, item, nil})
+				}
+			default:
+				return nil, fmt.Errorf("%s: object %d: unknown type %q", mapPath, obj.ID, obj.Kind())
+			}
+		}
+	}
+	al.biomeName = strings.TrimSuffix(filepath.Base(mapPath), filepath.Ext(mapPath))
+	al.biome.BackgroundLayers = [3]int{int(graphics.Background1), int(graphics.Background2), int(graphics.Background3)}
+	al.biome.SkyColor = [3]uint8{135, 205, 235}
+	if sky, ok := parseTiledColor(m.BackgroundColor); ok {
+		al.biome.SkyColor = [3]uint8{sky.R, sky.G, sky.B}
+	}
+	return al, nil
+}
+
+func spriteFor(sprites SpriteLocator, ts *tiled.Tileset, id uint32, props tiled.Properties) (graphics.SpriteID, error) {
+	if props.Has("sprite") {
+		sprite := props.Int("sprite", 0)
+		if sprite < 0 || sprite >= int(graphics.Final) {
+			return 0, fmt.Errorf("tileset %q: tile %d: sprite %d does not exist", ts.Name, id, sprite)
+		}
+		return graphics.SpriteID(sprite), nil
+	}
+	if filepath.Base(ts.Image.Source) == "spritesheet.png" {
+		px, py := ts.TileOrigin(id)
+		if sprite, ok := sprites.GetSpriteAt(px, py); ok {
+			return sprite, nil
+		}
+	}
+	return 0, fmt.Errorf("tileset %q: tile %d has no sprite property and is not a sprite in spritesheet.json", ts.Name, id)
+}
+
+// Tiled writes colours as #RRGGBB or #AARRGGBB
+func parseTiledColor(s string) (color.RGBA, bool) {
+	var r, g, b, a uint8
+	switch len(s) {
+	case 7:
+		if _, err := fmt.Sscanf(s, "#%02x%02x%02x", &r, &g, &b); err != nil {
+			return color.RGBA{}, false
+		}
+	case 9:
+		if _, err := fmt.Sscanf(s, "#%02x%02x%02x%02x", &a, &r, &g, &b); err != nil {
+			return color.RGBA{}, false
+		}
+	default:
+		return color.RGBA{}, false
+	}
+	return color.RGBA{r, g, b, 255}, true
+}
+
+// Columns past the right edge of the map are open air
+func (al *AuthoredLevel) Column(x uint32) Column {
+	if x < al.Width() {
+		return al.columns[x]
+	}
+	return airColumn(x, al.height)
+}
+
+func (al *AuthoredLevel) Update(worldXStart, worldXEnd uint32) []SpawnEvent {
+	var spawns []SpawnEvent
+	timeNow := time.Now().UnixMilli()
+	for _, s := range al.spawners {
+		col := uint32(s.x / TILEWIDTH)
+		if col < worldXStart || col > worldXEnd {
+			continue
+		}
+		if s.alive < s.count && timeNow > s.lastSpawn+s.intervalMs {
+			spawns = append(spawns, SpawnEvent{ZombieSpawn, s.x, s.y, "", s})
+			s.alive++
+			s.lastSpawn = timeNow
+		}
+	}
+	return spawns
+}
+
+func (al *AuthoredLevel) Width() uint32 {
+	return uint32(len(al.columns))
+}
+
+func (al *AuthoredLevel) Height() uint32 {
+	return al.height
+}
+
+func (al *AuthoredLevel) PlayerStart() (float32, float32) {
+	return al.playerStartX, al.playerStartY
+}
+
+// Complete once a player reaches the exit, or the end of the map comes into view if it has none
+func (al *AuthoredLevel) IsComplete(worldXEnd uint32, furthestPlayerX float32) bool {
+	if al.hasExit {
+		return furthestPlayerX >= al.exitX
+	}
+	return worldXEnd >= al.Width()
+}
+
+func (al *AuthoredLevel) BlendAt(worldX float32) (*common.BiomeJson, *common.BiomeJson, float64) {
+	return &al.biome, &al.biome, 1
+}
+
+func (al *AuthoredLevel) BiomeAt(worldX float32) string {
+	return al.biomeName
+}
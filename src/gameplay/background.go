@@ -8,63 +8,77 @@ import (
 // Background for parallax tings
 type Background struct {
 	world                                        *World
-	third, second, first                         *ebiten.Image
-	width, height                                float32
 	thirdModifier, secondModifier, firstModifier float32
 }
 
 func NewBackground(world *World) *Background {
 	b := &Background{world: world}
-	b.first = world.gdl.GetSpriteImage(graphics.Background1)
-	b.second = world.gdl.GetSpriteImage(graphics.Background2)
-	b.third = world.gdl.GetSpriteImage(graphics.Background3)
-	b.width = float32(b.third.Bounds().Max.X - b.third.Bounds().Min.X)
-	b.height = float32(b.third.Bounds().Max.Y - b.third.Bounds().Min.Y)
 	b.firstModifier = .05
 	b.secondModifier = .2
 	b.thirdModifier = .4
 	return b
 }
 
+// Cross fades between the background layers of the biomes the camera is moving between
 func (bg *Background) Draw(screen *ebiten.Image) {
-	sizeScale := bg.world.camera.screenHeight / bg.height
-	newWidth := sizeScale * bg.width
-	newHeight := sizeScale * bg.height
+	from, to, t := bg.world.level.BlendAt(bg.world.camera.CenterX())
+	if from.BackgroundLayers == to.BackgroundLayers {
+		t = 1
+	}
+	if t < 1 {
+		bg.drawLayers(screen, from.BackgroundLayers, 1)
+	}
+	if t > 0 {
+		bg.drawLayers(screen, to.BackgroundLayers, t)
+	}
+}
+
+func (bg *Background) drawLayers(screen *ebiten.Image, layers [3]int, alpha float64) {
+	first := bg.world.gdl.GetSpriteImage(graphics.SpriteID(layers[0]))
+	second := bg.world.gdl.GetSpriteImage(graphics.SpriteID(layers[1]))
+	third := bg.world.gdl.GetSpriteImage(graphics.SpriteID(layers[2]))
+	width := float32(third.Bounds().Max.X - third.Bounds().Min.X)
+	height := float32(third.Bounds().Max.Y - third.Bounds().Min.Y)
+
+	sizeScale := bg.world.camera.screenHeight / height
+	newWidth := sizeScale * width
+	newHeight := sizeScale * height
 	requiredTiles := 3 * bg.world.camera.screenWidth / newWidth
 
 	cOffX := bg.world.camera.offX
 	cOffY := bg.world.camera.offY
 
-	screenTLX := float64(int(cOffX*bg.firstModifier-bg.width/2) % int(newWidth))
-	screenTLY := float64(int(cOffY*bg.firstModifier*.25-bg.height/2) % int(newHeight))
+	screenTLX := float64(int(cOffX*bg.firstModifier-width/2) % int(newWidth))
+	screenTLY := float64(int(cOffY*bg.firstModifier*.25-height/2) % int(newHeight))
 
 	op := ebiten.DrawImageOptions{}
+	op.ColorM.Scale(1, 1, 1, alpha)
 	op.GeoM.Scale(float64(sizeScale), float64(sizeScale))
 	op.GeoM.Translate(screenTLX, screenTLY+float64(bg.world.camera.screenHeight)*.5)
 	for x := 0; x < int(requiredTiles); x++ {
-		screen.DrawImage(bg.first, &op)
+		screen.DrawImage(first, &op)
 		op.GeoM.Translate(float64(newWidth), 0)
 	}
 
-	screenTLX = float64(int(cOffX*bg.secondModifier-bg.width/2) % int(newWidth))
-	screenTLY = float64(int(cOffY*bg.secondModifier*.25-bg.height/2) % int(newHeight))
+	screenTLX = float64(int(cOffX*bg.secondModifier-width/2) % int(newWidth))
+	screenTLY = float64(int(cOffY*bg.secondModifier*.25-height/2) % int(newHeight))
 
 	op.GeoM.Reset()
 	op.GeoM.Scale(float64(sizeScale), float64(sizeScale))
 	op.GeoM.Translate(screenTLX, screenTLY+float64(bg.world.camera.screenHeight)*.525)
 	for x := 0; x < int(requiredTiles); x++ {
-		screen.DrawImage(bg.second, &op)
+		screen.DrawImage(second, &op)
 		op.GeoM.Translate(float64(newWidth), 0)
 	}
 
-	screenTLX = float64(int(cOffX*bg.thirdModifier-bg.width/2) % int(newWidth))
-	screenTLY = float64(int(cOffY*bg.thirdModifier*.25-bg.height/2) % int(newHeight))
+	screenTLX = float64(int(cOffX*bg.thirdModifier-width/2) % int(newWidth))
+	screenTLY = float64(int(cOffY*bg.thirdModifier*.25-height/2) % int(newHeight))
 
 	op.GeoM.Reset()
 	op.GeoM.Scale(float64(sizeScale), float64(sizeScale))
 	op.GeoM.Translate(screenTLX, screenTLY+float64(bg.world.camera.screenHeight)*.55)
 	for x := 0; x < int(requiredTiles); x++ {
-		screen.DrawImage(bg.third, &op)
+		screen.DrawImage(third, &op)
 		op.GeoM.Translate(float64(newWidth), 0)
 	}
 }
@@ -0,0 +1,118 @@
+package gameplay
+
+import (
+	"image/color"
+	"math/rand"
+
+	"github.com/Jack-Craig/gogame/src/common"
+)
+
+// Variable length themes of tile chunks
+type Biome struct {
+	common.BiomeJson
+	// string key in BiomeDataJson
+	biomeType string
+	// Where did this biome start, in array coords
+	startX uint32
+	// How many columns this biome lasts, between MinLength and MaxLength
+	length uint32
+	// Floor height the heightmap is sampled around, not always a whole tile so it can be lined up with a chunk
+	baseHeight float64
+	// At the end of this biome, what is the floor height
+	floorHeight uint32
+	// The biome before this one, blended out of over the first few columns
+	prev           common.BiomeJson
+	prevBaseHeight float64
+	hasPrev        bool
+}
+
+// Resets b to a new biome of type biomeType starting at startX, following prev if there is one
+func (b *Biome) start(biomeType string, bj common.BiomeJson, startX, floorHeight uint32, prev *Biome, rng *rand.Rand) {
+	b.BiomeJson = bj
+	b.biomeType = biomeType
+	b.startX = startX
+	b.length = bj.MinLength
+	if bj.MaxLength > bj.MinLength {
+		b.length += uint32(rng.Intn(int(bj.MaxLength-bj.MinLength) + 1))
+	}
+	b.baseHeight = float64(floorHeight)
+	b.floorHeight = floorHeight
+	b.hasPrev = prev != nil
+	if prev != nil {
+		b.prev = prev.BiomeJson
+		b.prevBaseHeight = prev.baseHeight
+	} else {
+		b.prev = bj
+		b.prevBaseHeight = float64(floorHeight)
+	}
+}
+
+// Picks the type of the biome that follows b, weighted by its nextTo edges
+func (b *Biome) pickNext(rng *rand.Rand) string {
+	names := common.SortedKeys(b.NextTo)
+	var total float64
+	for _, name := range names {
+		total += b.NextTo[name]
+	}
+	r := rng.Float64() * total
+	for _, name := range names {
+		r -= b.NextTo[name]
+		if r < 0 {
+			return name
+		}
+	}
+	return names[len(names)-1]
+}
+
+// Returns the generated biome containing column x, or nil if it has been overwritten or not generated yet
+func (l *Level) biomeAt(x uint32) *Biome {
+	var found *Biome
+	for i := range l.biomes {
+		b := &l.biomes[i]
+		if b.biomeType == "" || b.startX > x || b.startX+b.length <= x {
+			continue
+		}
+		if found == nil || b.startX > found.startX {
+			found = b
+		}
+	}
+	return found
+}
+
+func (l *Level) BlendAt(worldX float32) (*common.BiomeJson, *common.BiomeJson, float64) {
+	if worldX < 0 {
+		worldX = 0
+	}
+	l.biomesMu.RLock()
+	defer l.biomesMu.RUnlock()
+	b := l.biomeAt(uint32(worldX / TILEWIDTH))
+	if b == nil {
+		b = &l.biomes[l.curBiomeIdx]
+	}
+	t := float64(worldX/TILEWIDTH-float32(b.startX)) / float64(SKYBLENDLENGTH)
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	return &b.prev, &b.BiomeJson, t
+}
+
+func (l *Level) BiomeAt(worldX float32) string {
+	if worldX < 0 {
+		worldX = 0
+	}
+	l.biomesMu.RLock()
+	defer l.biomesMu.RUnlock()
+	if b := l.biomeAt(uint32(worldX / TILEWIDTH)); b != nil {
+		return b.biomeType
+	}
+	return l.biomes[l.curBiomeIdx].biomeType
+}
+
+func skyColor(from, to *common.BiomeJson, t float64) color.RGBA {
+	lerp := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*t)
+	}
+	return color.RGBA{lerp(from.SkyColor[0], to.SkyColor[0]), lerp(from.SkyColor[1], to.SkyColor[1]), lerp(from.SkyColor[2], to.SkyColor[2]), 255}
+}
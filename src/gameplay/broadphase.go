@@ -0,0 +1,106 @@
+package gameplay
+
+import "sort"
+
+// Sweep and prune on x. Entities are kept sorted by their left edge, so each one only needs testing
+// against those that start before it ends. The order barely changes between ticks, so insertion sort is near linear
+type Broadphase struct {
+	sorted []*Entity
+	minX   []float32
+	// Widest bounding box, so Overlapping knows how far left to start looking
+	maxWidth float32
+	// Scratch set for sync, kept so it does not allocate every tick
+	present map[*Entity]bool
+}
+
+// Calls fn once for every pair of entities whose bounding boxes overlap
+func (bp *Broadphase) Pairs(entities []*Entity, fn func(a, b *Entity)) {
+	bp.Sort(entities)
+	for i, ei := range bp.sorted {
+		_, iMinY, iMaxX, iMaxY := ei.bounds()
+		for j := i + 1; j < len(bp.sorted) && bp.minX[j] <= iMaxX; j++ {
+			ej := bp.sorted[j]
+			_, jMinY, _, jMaxY := ej.bounds()
+			if iMaxY < jMinY || jMaxY < iMinY {
+				continue
+			}
+			fn(ei, ej)
+		}
+	}
+}
+
+// Sorts entities by their left edge, for Pairs and Overlapping
+func (bp *Broadphase) Sort(entities []*Entity) {
+	bp.sync(entities)
+	for i := 1; i < len(bp.sorted); i++ {
+		e, minX := bp.sorted[i], bp.minX[i]
+		j := i - 1
+		for ; j >= 0 && bp.minX[j] > minX; j-- {
+			bp.sorted[j+1], bp.minX[j+1] = bp.sorted[j], bp.minX[j]
+		}
+		bp.sorted[j+1], bp.minX[j+1] = e, minX
+	}
+}
+
+// Calls fn with every entity from the last Sort whose bounding box reaches between minX and maxX
+func (bp *Broadphase) Overlapping(minX, maxX float32, fn func(e *Entity)) {
+	start := sort.Search(len(bp.minX), func(i int) bool {
+		return bp.minX[i] >= minX-bp.maxWidth
+	})
+	for i := start; i < len(bp.sorted) && bp.minX[i] <= maxX; i++ {
+		if _, _, eMaxX, _ := bp.sorted[i].bounds(); eMaxX >= minX {
+			fn(bp.sorted[i])
+		}
+	}
+}
+
+// Keeps the previous tick's order for entities still in the world and appends new ones
+func (bp *Broadphase) sync(entities []*Entity) {
+	if bp.present == nil {
+		bp.present = make(map[*Entity]bool, len(entities))
+	}
+	present := bp.present
+	clear(present)
+	for _, e := range entities {
+		present[e] = true
+	}
+	kept := bp.sorted[:0]
+	for _, e := range bp.sorted {
+		if present[e] {
+			kept = append(kept, e)
+			delete(present, e)
+		}
+	}
+	for _, e := range entities {
+		if present[e] {
+			kept = append(kept, e)
+		}
+	}
+	bp.sorted = kept
+	bp.minX = bp.minX[:0]
+	bp.maxWidth = 0
+	for _, e := range bp.sorted {
+		minX, _, maxX, _ := e.bounds()
+		bp.minX = append(bp.minX, minX)
+		bp.maxWidth = max(bp.maxWidth, maxX-minX)
+	}
+}
+
+// Axis aligned box around the entity's corners
+func (e *Entity) bounds() (float32, float32, float32, float32) {
+	corners := e.Corners()
+	minX, minY, maxX, maxY := corners[0].X, corners[0].Y, corners[0].X, corners[0].Y
+	for _, c := range corners[1:] {
+		if c.X < minX {
+			minX = c.X
+		} else if c.X > maxX {
+			maxX = c.X
+		}
+		if c.Y < minY {
+			minY = c.Y
+		} else if c.Y > maxY {
+			maxY = c.Y
+		}
+	}
+	return float32(minX), float32(minY), float32(maxX), float32(maxY)
+}
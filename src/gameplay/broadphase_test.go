@@ -0,0 +1,114 @@
+package gameplay
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// Zombie and bullet sized boxes scattered over a stretch of level a few screens wide
+func scatterEntities(n int, rng *rand.Rand) []*Entity {
+	entities := make([]*Entity, n)
+	for i := range entities {
+		size := TILEWIDTH - 1
+		if i%2 == 1 {
+			size = 18
+		}
+		x := rng.Float32() * 100 * TILEWIDTH
+		y := rng.Float32() * float32(LEVELHEIGHT) * TILEWIDTH
+		entities[i] = NewEntity(x, y, size, size, 0, nil, nil, false)
+	}
+	return entities
+}
+
+// Moves everything a little, as a tick of physics would
+func jiggle(entities []*Entity, rng *rand.Rand) {
+	for _, e := range entities {
+		e.x += rng.Float32()*8 - 4
+		e.y += rng.Float32()*8 - 4
+	}
+}
+
+// Tests every pair, what Broadphase replaced
+func bruteForcePairs(entities []*Entity, fn func(a, b *Entity)) {
+	for i, a := range entities {
+		aMinX, aMinY, aMaxX, aMaxY := a.bounds()
+		for _, b := range entities[i+1:] {
+			bMinX, bMinY, bMaxX, bMaxY := b.bounds()
+			if aMaxX < bMinX || bMaxX < aMinX || aMaxY < bMinY || bMaxY < aMinY {
+				continue
+			}
+			fn(a, b)
+		}
+	}
+}
+
+func TestBroadphaseMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	entities := scatterEntities(300, rng)
+	var bp Broadphase
+	for tick := 0; tick < 10; tick++ {
+		want := make(map[[2]*Entity]bool)
+		bruteForcePairs(entities, func(a, b *Entity) {
+			want[[2]*Entity{a, b}] = true
+		})
+		got := 0
+		bp.Pairs(entities, func(a, b *Entity) {
+			if !want[[2]*Entity{a, b}] && !want[[2]*Entity{b, a}] {
+				t.Errorf("tick %d: unexpected pair at %v,%v and %v,%v", tick, a.x, a.y, b.x, b.y)
+			}
+			got++
+		})
+		if got != len(want) {
+			t.Errorf("tick %d: %d pairs, expected %d", tick, got, len(want))
+		}
+		jiggle(entities, rng)
+	}
+}
+
+func BenchmarkPairs(b *testing.B) {
+	for _, n := range []int{100, 300, 1000} {
+		b.Run(fmt.Sprintf("bruteforce/%d", n), func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1))
+			entities := scatterEntities(n, rng)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				jiggle(entities, rng)
+				bruteForcePairs(entities, func(a, b *Entity) {})
+			}
+		})
+		b.Run(fmt.Sprintf("sweepandprune/%d", n), func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1))
+			entities := scatterEntities(n, rng)
+			var bp Broadphase
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				jiggle(entities, rng)
+				bp.Pairs(entities, func(a, b *Entity) {})
+			}
+		})
+	}
+}
+
+func TestOverlappingMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	entities := scatterEntities(300, rng)
+	var bp Broadphase
+	bp.Sort(entities)
+	for i := 0; i < 50; i++ {
+		minX := rng.Float32() * 100 * TILEWIDTH
+		maxX := minX + rng.Float32()*4*TILEWIDTH
+		found := make(map[*Entity]bool)
+		bp.Overlapping(minX, maxX, func(e *Entity) {
+			found[e] = true
+		})
+		for _, e := range entities {
+			eMinX, _, eMaxX, _ := e.bounds()
+			if want := eMaxX >= minX && eMinX <= maxX; want != found[e] {
+				t.Errorf("%v to %v: entity spanning %v to %v found %v, expected %v", minX, maxX, eMinX, eMaxX, found[e], want)
+			}
+		}
+	}
+}
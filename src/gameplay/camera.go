@@ -19,18 +19,34 @@ func (c *Camera) Update() {
 	if c.screenHeight == 0 || c.screenWidth == 0 {
 		return
 	}
+	if c.w.arena != nil {
+		// The arena fits on one screen, pinned to the bottom
+		c.offX = 0
+		c.offY = c.screenHeight - float32(c.w.level.Height())*TILEWIDTH
+		return
+	}
+	// Follow the players still in the world
 	var totalX, totalY float32
-	for _, player := range c.w.playerObjects {
+	n := 0
+	c.w.inputs.Each(func(id EntityID, player *Player) {
 		totalX += player.x + player.width/2
 		totalY += player.y + player.height/2
+		n++
+	})
+	if n == 0 {
+		return
 	}
-	newXOffset := -totalX/float32(len(c.w.playerObjects)) + c.screenWidth/2
-	// Only able to move right, cant see outside of world on right
-	if newXOffset < c.offX && int(c.w.level.worldXEnd) < int(c.w.level.worldWidth) {
+	newXOffset := -totalX/float32(n) + c.screenWidth/2
+	// Can backtrack to the start of the level, cant see outside of world on right
+	if newXOffset > 0 {
+		newXOffset = 0
+	}
+	if newXOffset > c.offX || int(c.w.worldXEnd) < int(c.w.level.Width()) {
 		c.offX = newXOffset
 	}
-	newYOffset := -totalY/float32(len(c.w.playerObjects)) + c.screenHeight*2/3
-	if float32(WORLDBUFFERHEIGHT*uint32(TILEWIDTH)) > -newYOffset+c.screenHeight {
+	newYOffset := -totalY/float32(n) + c.screenHeight*2/3
+	// Cant see below the bottom of the level
+	if float32(c.w.level.Height())*TILEWIDTH > -newYOffset+c.screenHeight {
 		c.offY = newYOffset
 	}
 
@@ -41,6 +57,11 @@ func (c *Camera) GetRenderOffset() (float32, float32) {
 	return c.offX, c.offY
 }
 
+// World x coordinate at the middle of the screen
+func (c *Camera) CenterX() float32 {
+	return -c.offX + c.screenWidth/2
+}
+
 // Returns true if the coordinates are within the camera bounds
 func (c *Camera) IsInsideCamera(x, y float32) bool {
 	topLeftX := -c.offX
@@ -0,0 +1,159 @@
+package gameplay
+
+import "math"
+
+// Chunk coordinates, in units of CHUNKSIZE tiles
+type ChunkCoord struct {
+	x, y int32
+}
+
+// Everything the level has placed in a chunk, kept while the chunk is unloaded so it comes back as it was left
+type chunkData struct {
+	tiles [CHUNKSIZE][CHUNKSIZE]TileSpec
+	// Damage taken by breakable tiles
+	damage [CHUNKSIZE][CHUNKSIZE]float32
+	// Waiting for the chunk to be loaded for the first time
+	spawns []SpawnEvent
+}
+
+func newChunkData() *chunkData {
+	data := &chunkData{}
+	for y := range data.tiles {
+		for x := range data.tiles[y] {
+			data.tiles[y][x] = airSpec
+		}
+	}
+	return data
+}
+
+// A loaded chunk of tiles, indexed [y][x]
+type TileChunk struct {
+	coord ChunkCoord
+	tiles [CHUNKSIZE][CHUNKSIZE]*Tile
+}
+
+func newTileChunk(coord ChunkCoord, data *chunkData, w *World) *TileChunk {
+	c := &TileChunk{coord: coord}
+	for ly := int32(0); ly < CHUNKSIZE; ly++ {
+		for lx := int32(0); lx < CHUNKSIZE; lx++ {
+			x := float32(coord.x*CHUNKSIZE+lx) * TILEWIDTH
+			y := float32(coord.y*CHUNKSIZE+ly) * TILEWIDTH
+			tile := NewTile(0, x, y, w, nil)
+			tile.SetSpec(data.tiles[ly][lx])
+			if tile.isBreakable {
+				tile.health -= data.damage[ly][lx]
+			}
+			c.tiles[ly][lx] = tile
+		}
+	}
+	return c
+}
+
+// Writes the tiles' current state back so the chunk can be dropped
+func (c *TileChunk) save(data *chunkData) {
+	for ly := range c.tiles {
+		for lx, tile := range c.tiles[ly] {
+			data.tiles[ly][lx] = tile.Spec()
+			data.damage[ly][lx] = 0
+			if tile.isBreakable {
+				data.damage[ly][lx] = tile.maxHealth - tile.health
+			}
+		}
+	}
+}
+
+// Grid coordinates of the tile containing world coordinates x, y
+func toGrid(x, y float32) (int32, int32) {
+	return int32(math.Floor(float64(x / TILEWIDTH))), int32(math.Floor(float64(y / TILEWIDTH)))
+}
+
+// Chunk holding grid coordinates gx, gy, and the tile's position within it
+func toChunk(gx, gy int32) (ChunkCoord, int32, int32) {
+	coord := ChunkCoord{floorDiv(gx, CHUNKSIZE), floorDiv(gy, CHUNKSIZE)}
+	return coord, gx - coord.x*CHUNKSIZE, gy - coord.y*CHUNKSIZE
+}
+
+func floorDiv(a, b int32) int32 {
+	if a < 0 {
+		return (a - b + 1) / b
+	}
+	return a / b
+}
+
+// Returns the tile at grid coordinates gx, gy, or nil if its chunk is not loaded
+func (w *World) tileAtGrid(gx, gy int32) *Tile {
+	coord, lx, ly := toChunk(gx, gy)
+	chunk := w.chunks[coord]
+	if chunk == nil {
+		return nil
+	}
+	return chunk.tiles[ly][lx]
+}
+
+// Grid coordinates of the tiles on screen, inclusive
+func (w *World) visibleGrid() (int32, int32, int32, int32) {
+	offX, offY := w.camera.GetRenderOffset()
+	minX, minY := toGrid(-offX, -offY)
+	maxX, maxY := toGrid(-offX+w.camera.screenWidth, -offY+w.camera.screenHeight)
+	return minX, minY, maxX, maxY
+}
+
+// Loads chunks within CHUNKLOADMARGIN of the screen and drops those further than CHUNKUNLOADMARGIN
+func (w *World) updateChunks() {
+	minX, minY, maxX, maxY := w.visibleGrid()
+	minChunk, _, _ := toChunk(minX, minY)
+	maxChunk, _, _ := toChunk(maxX, maxY)
+
+	for coord, chunk := range w.chunks {
+		if coord.x < minChunk.x-CHUNKUNLOADMARGIN || coord.x > maxChunk.x+CHUNKUNLOADMARGIN ||
+			coord.y < minChunk.y-CHUNKUNLOADMARGIN || coord.y > maxChunk.y+CHUNKUNLOADMARGIN {
+			chunk.save(w.chunkData[coord])
+			delete(w.chunks, coord)
+		}
+	}
+	for cy := minChunk.y - CHUNKLOADMARGIN; cy <= maxChunk.y+CHUNKLOADMARGIN; cy++ {
+		for cx := minChunk.x - CHUNKLOADMARGIN; cx <= maxChunk.x+CHUNKLOADMARGIN; cx++ {
+			coord := ChunkCoord{cx, cy}
+			if w.chunks[coord] != nil {
+				continue
+			}
+			// Nothing has been placed here, it is open air
+			data := w.chunkData[coord]
+			if data == nil {
+				continue
+			}
+			w.chunks[coord] = newTileChunk(coord, data, w)
+			for _, spawn := range data.spawns {
+				w.spawn(spawn)
+			}
+			data.spawns = nil
+		}
+	}
+}
+
+// True if e overlaps, or is about to move into, a chunk with tiles that is not loaded
+func (w *World) nearUnloadedChunk(e *Entity) bool {
+	minX, minY := toGrid(min(e.x, e.x+e.vx), min(e.y, e.y+e.vy))
+	maxX, maxY := toGrid(max(e.x, e.x+e.vx)+e.width, max(e.y, e.y+e.vy)+e.height)
+	minChunk, _, _ := toChunk(minX, minY)
+	maxChunk, _, _ := toChunk(maxX, maxY)
+	for cy := minChunk.y; cy <= maxChunk.y; cy++ {
+		for cx := minChunk.x; cx <= maxChunk.x; cx++ {
+			coord := ChunkCoord{cx, cy}
+			if w.chunks[coord] == nil && w.chunkData[coord] != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Returns the stored data for a chunk, creating it as open air if nothing has been placed there yet
+func (w *World) chunkDataAt(coord ChunkCoord) *chunkData {
+	data := w.chunkData[coord]
+	if data == nil {
+		data = newChunkData()
+		w.chunkData[coord] = data
+	}
+	return data
+}
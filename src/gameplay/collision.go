@@ -0,0 +1,94 @@
+package gameplay
+
+import (
+	"math"
+
+	"github.com/Jack-Craig/gogame/src/common"
+)
+
+// Bit flags for what an entity is and what it collides with
+type CollisionLayer uint16
+
+const (
+	PlayerLayer CollisionLayer = 1 << iota
+	ZombieLayer
+	ProjectileLayer
+	PickupLayer
+)
+
+// How two overlapping entities touch
+type Manifold struct {
+	a, b *Entity
+	// Unit contact normal, pointing from a to b
+	normal common.Vec2
+	// Distance b has to move along normal to stop overlapping a
+	depth float64
+}
+
+// True if a and b should be tested at all. Both have to want the other's layer, and nothing hits its owner
+func canCollide(a, b *Entity) bool {
+	if a.mask&b.layer == 0 || b.mask&a.layer == 0 {
+		return false
+	}
+	return a.owner != b && b.owner != a
+}
+
+// Separating axis test between two entities' rotated boxes. Returns the minimum translation to separate them
+func collide(a, b *Entity) (Manifold, bool) {
+	aCorners, bCorners := a.Corners(), b.Corners()
+	m := Manifold{a: a, b: b, depth: math.Inf(1)}
+	for _, normals := range [][]common.Vec2{a.normals, b.normals} {
+		for _, norm := range normals {
+			mina, maxa := common.ProjectPoints(aCorners[:], norm)
+			minb, maxb := common.ProjectPoints(bCorners[:], norm)
+			if maxa < minb || maxb < mina {
+				return Manifold{}, false
+			}
+			overlap := math.Min(maxa, maxb) - math.Max(mina, minb)
+			if overlap < m.depth {
+				m.depth = overlap
+				m.normal = norm
+			}
+		}
+	}
+	// Point the normal from a to b
+	aX, aY := a.Center()
+	bX, bY := b.Center()
+	if common.Dot(common.NewVec2(float64(bX-aX), float64(bY-aY)), m.normal) < 0 {
+		m.normal = common.Neg(m.normal)
+	}
+	return m, true
+}
+
+// Records the contact on both entities, and pushes solid bodies apart half each
+func (m Manifold) resolve() {
+	m.a.collidingEntities = append(m.a.collidingEntities, m.b)
+	m.b.collidingEntities = append(m.b.collidingEntities, m.a)
+	if m.a.isTrigger || m.b.isTrigger {
+		return
+	}
+	half := float32(m.depth / 2)
+	nx, ny := float32(m.normal.X), float32(m.normal.Y)
+	m.a.nudge(-nx*half, -ny*half)
+	m.b.nudge(nx*half, ny*half)
+}
+
+// Moves the entity by dx, dy without pushing it into the world, one axis at a time
+func (e *Entity) nudge(dx, dy float32) {
+	if !e.overlapsWorldAt(e.x+dx, e.y) {
+		e.x += dx
+	}
+	if !e.overlapsWorldAt(e.x, e.y+dy) {
+		e.y += dy
+	}
+}
+
+// True if any rotated corner would be in the world with the entity's top left at x, y
+func (e *Entity) overlapsWorldAt(x, y float32) bool {
+	for _, c := range e.Corners() {
+		if e.w.IsWorldCollision(float32(c.X)+x-e.x, float32(c.Y)+y-e.y) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package gameplay
+
+import (
+	"github.com/Jack-Craig/gogame/src/common"
+	"github.com/Jack-Craig/gogame/src/graphics"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Position, size and rotation about the top left
+type Transform struct {
+	x, y, width, height float32
+	theta               float64
+	normals             []common.Vec2
+}
+
+func NewTransform(x, y, width, height float32, theta float64) *Transform {
+	t := &Transform{x, y, width, height, theta, make([]common.Vec2, 2)}
+	t.CalcNormals()
+	return t
+}
+
+// Edge normals of the rotated box, call whenever theta changes. Opposite edges share a normal so two are enough
+func (t *Transform) CalcNormals() {
+	t.normals[0], t.normals[1] = common.BoxAxes(t.theta)
+}
+
+func (t *Transform) SetTheta(theta float64) {
+	t.theta = theta
+	t.CalcNormals()
+}
+
+// Corners of the box rotated by theta about its top left, as it is drawn. Clockwise from the top left
+func (t *Transform) Corners() [4]common.Vec2 {
+	return common.BoxCorners(float64(t.x), float64(t.y), float64(t.width), float64(t.height), t.theta)
+}
+
+func (t *Transform) Center() (float32, float32) {
+	c := t.Corners()
+	return float32(c[0].X+c[2].X) / 2, float32(c[0].Y+c[2].Y) / 2
+}
+
+// Movement, moved through the world by PhysicsSystem
+type Velocity struct {
+	vx, vy            float32
+	gravityMultiplier float32
+	facingDir         common.Vec2
+	// Gravity is suspended while climbing
+	isClimbing bool
+	// Landed on something last update
+	onGround bool
+}
+
+// Collision with other entities, tested by CollisionSystem
+type Collider struct {
+	// What this is, and what it collides with
+	layer, mask CollisionLayer
+	// Overlaps are recorded but nothing is pushed apart
+	isTrigger bool
+	// Never collides with the entity that made it
+	owner *Entity
+	// Maintained by CollisionSystem every tick
+	collidingEntities []*Entity
+	immuneToGuns      bool
+}
+
+// Removed from the world when it reaches 0, unless it is downed
+type Health struct {
+	health float32
+	// Milliseconds, set by TakeDamage
+	stunnedUntil, invulnerableUntil, lastHitTime int64
+	// How long TakeDamage makes this invulnerable for, 0 for never
+	iFramesMs int64
+	// How long this lies downed at 0 health before dying, 0 to die straight away
+	bleedOutMs int64
+	isDowned   bool
+	// Milliseconds, when a downed entity dies unless it is being revived
+	bleedOutAt int64
+	// Credited with the kill if it bleeds out
+	downedBy *Entity
+}
+
+// How the entity is drawn, a still image or walk and idle animations
+type Sprite struct {
+	im            *ebiten.Image
+	hasAnimation  bool
+	walkAnimation graphics.Animation
+	idleAnimation graphics.Animation
+}
@@ -0,0 +1,70 @@
+package gameplay
+
+import (
+	"math"
+	"time"
+
+	"github.com/Jack-Craig/gogame/src/common"
+)
+
+// Applies damage and knockback from source, and stuns the entity briefly. Entities with i-frames then ignore
+// damage for a while. Returns false if the damage was ignored
+func (e *Entity) TakeDamage(source *Entity, amount float32, impulse common.Vec2) bool {
+	timeNow := time.Now().UnixMilli()
+	if timeNow < e.invulnerableUntil || e.health <= 0 {
+		return false
+	}
+	e.health -= amount
+	e.vx += float32(impulse.X)
+	e.vy += float32(impulse.Y)
+	e.isClimbing = false
+	e.stunnedUntil = timeNow + HITSTUNMS
+	e.lastHitTime = timeNow
+	if e.iFramesMs > 0 {
+		e.invulnerableUntil = timeNow + e.iFramesMs
+	}
+	e.w.events.Publish(EntityDamaged{e, source, amount, impulse})
+	if e.health <= 0 {
+		e.health = 0
+		if e.bleedOutMs > 0 {
+			e.isDowned = true
+			e.bleedOutAt = timeNow + e.bleedOutMs
+			e.downedBy = source
+			e.w.events.Publish(EntityDowned{e, source})
+		} else {
+			e.w.events.Publish(EntityKilled{e, source})
+		}
+	}
+	return true
+}
+
+// Drops the entity's health to 0, crediting source with the kill. Finishes off downed entities, and does nothing
+// if it is already dead
+func (e *Entity) Kill(source *Entity) {
+	if e.health <= 0 && !e.isDowned {
+		return
+	}
+	e.health = 0
+	e.isDowned = false
+	e.w.events.Publish(EntityKilled{e, source})
+}
+
+func (e *Entity) IsStunned() bool {
+	return time.Now().UnixMilli() < e.stunnedUntil
+}
+
+func (e *Entity) IsInvulnerable() bool {
+	return time.Now().UnixMilli() < e.invulnerableUntil
+}
+
+// Knockback of strength pointing from x, y towards the entity, tipped upwards so it leaves the ground
+func (e *Entity) knockbackFrom(x, y, strength float32) common.Vec2 {
+	cx, cy := e.Center()
+	dx, dy := float64(cx-x), float64(cy-y)
+	dist := math.Hypot(dx, dy)
+	if dist == 0 {
+		return common.NewVec2(0, -float64(strength))
+	}
+	dir := common.Normalize(common.NewVec2(dx/dist, dy/dist-.5))
+	return common.Scale(dir, float64(strength))
+}
@@ -0,0 +1,125 @@
+package gameplay
+
+// Identifies an entity in a World's registry. 0 is never handed out
+type EntityID uint32
+
+// Hands out entity ids and owns every component store, so destroying an entity takes it out of all of them
+type Registry struct {
+	nextID EntityID
+	stores []componentStore
+}
+
+type componentStore interface {
+	remove(id EntityID)
+	compact()
+}
+
+func (r *Registry) Create() EntityID {
+	r.nextID++
+	return r.nextID
+}
+
+// Removes every component id has. The gaps left behind are only closed by Compact, so destroying many entities
+// at once costs a single pass over each store
+func (r *Registry) Destroy(id EntityID) {
+	for _, s := range r.stores {
+		s.remove(id)
+	}
+}
+
+// Closes the gaps left by Destroy, keeping every store in order
+func (r *Registry) Compact() {
+	for _, s := range r.stores {
+		s.compact()
+	}
+}
+
+// Components of one type by entity id. Kept packed in the order they were added, so systems always visit
+// them in the same order
+type Store[T any] struct {
+	// Removed components leave id 0 behind until compact
+	ids     []EntityID
+	items   []T
+	index   map[EntityID]int
+	removed int
+}
+
+func NewStore[T any](r *Registry) *Store[T] {
+	s := &Store[T]{index: make(map[EntityID]int)}
+	r.stores = append(r.stores, s)
+	return s
+}
+
+// Gives id the component c, replacing any it already has
+func (s *Store[T]) Add(id EntityID, c T) {
+	if i, ok := s.index[id]; ok {
+		s.items[i] = c
+		return
+	}
+	s.index[id] = len(s.items)
+	s.ids = append(s.ids, id)
+	s.items = append(s.items, c)
+}
+
+func (s *Store[T]) Get(id EntityID) (T, bool) {
+	i, ok := s.index[id]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return s.items[i], true
+}
+
+// Calls fn with every component in order. Use World.AddEntity and World.Despawn rather than changing stores from fn
+func (s *Store[T]) Each(fn func(id EntityID, c T)) {
+	for i, c := range s.items {
+		if s.ids[i] != 0 {
+			fn(s.ids[i], c)
+		}
+	}
+}
+
+// Leaves a gap where id was, for compact to close
+func (s *Store[T]) remove(id EntityID) {
+	i, ok := s.index[id]
+	if !ok {
+		return
+	}
+	delete(s.index, id)
+	var zero T
+	s.ids[i] = 0
+	s.items[i] = zero
+	s.removed++
+}
+
+// Shifts everything down over the gaps, so the order stays the same
+func (s *Store[T]) compact() {
+	if s.removed == 0 {
+		return
+	}
+	n := 0
+	for i, id := range s.ids {
+		if id == 0 {
+			continue
+		}
+		if n != i {
+			s.ids[n] = id
+			s.items[n] = s.items[i]
+			s.index[id] = n
+		}
+		n++
+	}
+	var zero T
+	for i := n; i < len(s.items); i++ {
+		s.items[i] = zero
+	}
+	s.ids = s.ids[:n]
+	s.items = s.items[:n]
+	s.removed = 0
+}
+
+// Runs once per tick over the components it cares about. Systems run in order, each one seeing what the last
+// left behind, and may keep their own state between ticks
+type System interface {
+	Update(w *World)
+}
@@ -0,0 +1,39 @@
+package gameplay
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStoreKeepsOrderThroughRemoval(t *testing.T) {
+	var r Registry
+	s := NewStore[int](&r)
+	var ids []EntityID
+	for i := 0; i < 10; i++ {
+		id := r.Create()
+		s.Add(id, i)
+		ids = append(ids, id)
+	}
+	for _, i := range []int{0, 3, 4, 9} {
+		r.Destroy(ids[i])
+	}
+	var before []int
+	s.Each(func(id EntityID, c int) {
+		before = append(before, c)
+	})
+	r.Compact()
+	var after []int
+	s.Each(func(id EntityID, c int) {
+		after = append(after, c)
+		if got, ok := s.Get(id); !ok || got != c {
+			t.Errorf("Get(%d) is %d, %v after compacting, expected %d", id, got, ok, c)
+		}
+	})
+	expected := []int{1, 2, 5, 6, 7, 8}
+	if !slices.Equal(before, expected) || !slices.Equal(after, expected) {
+		t.Errorf("visited %v before compacting and %v after, expected %v", before, after, expected)
+	}
+	if _, ok := s.Get(ids[3]); ok {
+		t.Errorf("destroyed entity %d still has its component", ids[3])
+	}
+}
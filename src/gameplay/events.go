@@ -0,0 +1,112 @@
+package gameplay
+
+import "github.com/Jack-Craig/gogame/src/common"
+
+// Something that happened in the world. Subscribers get events with Subscribe, by their concrete type
+type Event interface {
+	isEvent()
+}
+
+// An entity lost health, source is nil for the world, such as the zombie wall
+type EntityDamaged struct {
+	target, source *Entity
+	amount         float32
+	impulse        common.Vec2
+}
+
+// An entity's health reached 0
+type EntityKilled struct {
+	target, source *Entity
+}
+
+// An entity's health reached 0 but it can still be revived
+type EntityDowned struct {
+	target, source *Entity
+}
+
+type PlayerRevived struct {
+	player, reviver *Player
+}
+
+type ShotFired struct {
+	shooter    *Player
+	projectile *Projectile
+}
+
+type PickupCollected struct {
+	player *Player
+	name   string
+	data   common.PickupJson
+}
+
+type PlayerJoined struct {
+	player *Player
+}
+
+// A player left the end of the level alive
+type PlayerFinished struct {
+	player *Player
+}
+
+// A dead player came back in the arena
+type PlayerRespawned struct {
+	player *Player
+}
+
+// A player from team finished the race first
+type RaceWon struct {
+	team int
+}
+
+// Every player has left the screen or died, survivors are the ones still alive
+type LevelCompleted struct {
+	survivors []*Player
+}
+
+// The camera moved into a different biome
+type BiomeEntered struct {
+	biome string
+}
+
+func (EntityDamaged) isEvent()   {}
+func (EntityKilled) isEvent()    {}
+func (EntityDowned) isEvent()    {}
+func (PlayerRevived) isEvent()   {}
+func (ShotFired) isEvent()       {}
+func (PickupCollected) isEvent() {}
+func (PlayerJoined) isEvent()    {}
+func (PlayerFinished) isEvent()  {}
+func (RaceWon) isEvent()         {}
+func (PlayerRespawned) isEvent() {}
+func (LevelCompleted) isEvent()  {}
+func (BiomeEntered) isEvent()    {}
+
+// Owned by World. Events published during a tick are queued and handed out in order when the tick ends,
+// so subscribers always see a finished tick and can publish more events of their own
+type EventBus struct {
+	subscribers []func(Event)
+	queue       []Event
+}
+
+// Calls fn with every published event of type T
+func Subscribe[T Event](bus *EventBus, fn func(T)) {
+	bus.subscribers = append(bus.subscribers, func(ev Event) {
+		if t, ok := ev.(T); ok {
+			fn(t)
+		}
+	})
+}
+
+func (bus *EventBus) Publish(ev Event) {
+	bus.queue = append(bus.queue, ev)
+}
+
+// Hands out everything queued, including events published by subscribers along the way
+func (bus *EventBus) Flush() {
+	for i := 0; i < len(bus.queue); i++ {
+		for _, fn := range bus.subscribers {
+			fn(bus.queue[i])
+		}
+	}
+	bus.queue = bus.queue[:0]
+}
@@ -12,86 +12,137 @@ import (
 
 // Game objects are anything that has a texture and location
 type GameObject struct {
-	id                  uint32
-	x, y, width, height float32
-	im                  *ebiten.Image
-	w                   *World
-	shouldRemove        bool
-	theta               float64
-	normals             []common.Vec2
-	hasAnimation        bool
+	id uint32
+	Transform
+	im           *ebiten.Image
+	w            *World
+	shouldRemove bool
+	hasAnimation bool
 }
 
 func NewGameObject(id uint32, x, y, width, height float32, theta float64, w *World, im *ebiten.Image, hasAnimation bool) *GameObject {
-	gameObj := &GameObject{
-		id, x, y, width, height, im, w, false, theta, nil, hasAnimation,
-	}
-	gameObj.normals = make([]common.Vec2, 4)
-	gameObj.CalcNormals()
-	return gameObj
-}
-
-func (gobj *GameObject) CalcNormals() {
-	projAxis := common.Normalize(common.NewVec2(1, math.Tan(gobj.theta)))
-
-	tl := common.NewVec2(float64(gobj.x), float64(gobj.y))
-	tr := common.NewVec2(float64(gobj.x+gobj.width), float64(gobj.y))
-	br := common.NewVec2(float64(gobj.x+gobj.width), float64(gobj.y+gobj.height))
-	bl := common.NewVec2(float64(gobj.x), float64(gobj.y+gobj.height))
-
-	tEdge := common.Normal(common.Normalize(common.NewVec2(1, common.Dot(projAxis, common.Sub(tl, tr)))))
-	rEdge := common.Normal(common.Normalize(common.NewVec2(1, common.Dot(projAxis, common.Sub(tr, br)))))
-	bEdge := common.Normal(common.Normalize(common.NewVec2(1, common.Dot(projAxis, common.Sub(br, bl)))))
-	lEdge := common.Normal(common.Normalize(common.NewVec2(1, common.Dot(projAxis, common.Sub(bl, tl)))))
-
-	gobj.normals[0] = tEdge
-	gobj.normals[1] = rEdge
-	gobj.normals[2] = bEdge
-	gobj.normals[3] = lEdge
+	return &GameObject{id, *NewTransform(x, y, width, height, theta), im, w, false, hasAnimation}
 }
 
 func (gobj *GameObject) Draw(screen *ebiten.Image) {
 	if gobj.im == nil || gobj.hasAnimation {
 		return
 	}
+	drawImage(screen, gobj.im, &gobj.Transform, gobj.w.camera)
+}
+
+// Draws im stretched over t's box, rotated with it
+func drawImage(screen, im *ebiten.Image, t *Transform, camera *Camera) {
 	op := ebiten.DrawImageOptions{}
-	camOffX, camOffY := gobj.w.camera.GetRenderOffset()
-	w, h := gobj.im.Size()
+	camOffX, camOffY := camera.GetRenderOffset()
+	w, h := im.Size()
 
-	op.GeoM.Scale(float64(gobj.width/float32(w)), float64(float32(gobj.height/float32(h))))
-	op.GeoM.Rotate(gobj.theta)
+	op.GeoM.Scale(float64(t.width/float32(w)), float64(float32(t.height/float32(h))))
+	op.GeoM.Rotate(t.theta)
 
-	op.GeoM.Translate(float64(gobj.x), float64(gobj.y))
+	op.GeoM.Translate(float64(t.x), float64(t.y))
 	op.GeoM.Translate(float64(camOffX), float64(camOffY))
-	screen.DrawImage(gobj.im, &op)
+	screen.DrawImage(im, &op)
 }
 
 // Tiles are game objects with collision, the world is made of tiles
 type Tile struct {
 	GameObject
+	sprite                  graphics.SpriteID
+	shape                   TileShape
+	material                string
 	isPassable, isClimbable bool
+	// Breakable tiles lose health to projectiles and explosions, and become passable at 0
+	isBreakable       bool
+	health, maxHealth float32
 }
 
 func NewTile(id uint32, x, y float32, w *World, im *ebiten.Image) *Tile {
 	return &Tile{
-		GameObject{id, x, y, TILEWIDTH, TILEWIDTH, im, w, false, 0, nil, false},
+		GameObject{id, Transform{x, y, TILEWIDTH, TILEWIDTH, 0, nil}, im, w, false, false},
+		0,
+		FullShape,
+		"",
+		false,
 		false,
 		false,
+		0,
+		0,
 	}
 }
 
-// Entities are similar to game objects but also have movement
+// Makes the tile breakable with full health. Called whenever the tile is (re)generated
+func (t *Tile) ResetHealth(maxHealth float32) {
+	t.isBreakable = maxHealth > 0
+	t.maxHealth = maxHealth
+	t.health = maxHealth
+}
+
+// Rebuilds the tile from a level's description of it, at full health
+func (t *Tile) SetSpec(spec TileSpec) {
+	t.sprite = spec.sprite
+	t.shape = spec.shape
+	t.material = spec.material
+	t.im = nil
+	if spec.hasSprite {
+		t.im = t.w.gdl.GetSpriteImage(spec.sprite)
+	}
+	t.isPassable = spec.isPassable
+	t.isClimbable = spec.isClimbable
+	if spec.isPassable {
+		t.ResetHealth(0)
+	} else {
+		t.ResetHealth(spec.health)
+	}
+}
+
+// Describes the tile as it is now, broken tiles come back as passable
+func (t *Tile) Spec() TileSpec {
+	return TileSpec{t.sprite, t.im != nil, t.isPassable, t.isClimbable, t.maxHealth, t.shape, t.material}
+}
+
+// Returns true if the damage broke the tile
+func (t *Tile) Damage(amount float32) bool {
+	if !t.isBreakable || t.isPassable {
+		return false
+	}
+	t.health -= amount
+	if t.health > 0 {
+		return false
+	}
+	t.w.SpawnDebris(t)
+	t.isPassable = true
+	t.isBreakable = false
+	t.im = nil
+	return true
+}
+
+// Entities are a handle on components in the world's registry, embedded so the common ones read like fields.
+// Anything left nil is a component the entity does not have
 type Entity struct {
-	GameObject
-	vx, vy                    float32
-	stayWithinCamera          bool
-	health, gravityMultiplier float32
-	// Maintained by world every Update()
-	collidingEntities []*Entity
-	immuneToGuns      bool
-	walkAnimation     graphics.Animation
-	idleAnimation     graphics.Animation
-	facingDir         common.Vec2
+	id EntityID
+	w  *World
+	// Destroyed at the end of the tick
+	shouldRemove bool
+	// Blocked by the edges of the screen instead of leaving it
+	stayWithinCamera bool
+	*Transform
+	*Velocity
+	*Collider
+	*Health
+	*Sprite
+}
+
+// An entity with every common component, not yet in a world
+func NewEntity(x, y, width, height float32, theta float64, w *World, im *ebiten.Image, hasAnimation bool) *Entity {
+	return &Entity{
+		w:         w,
+		Transform: NewTransform(x, y, width, height, theta),
+		Velocity:  &Velocity{},
+		Collider:  &Collider{},
+		Health:    &Health{},
+		Sprite:    &Sprite{im: im, hasAnimation: hasAnimation},
+	}
 }
 
 func (e *Entity) Update() {
@@ -117,12 +168,21 @@ func (e *Entity) Update() {
 		expectedX += e.width
 	}
 	collisionX, collisionY := e.WillCollideWithWorld()
+	cameraX := false
 	if e.stayWithinCamera {
 		if e.vx < 0 || !e.w.canLeave {
-			collisionX = collisionX || !e.w.camera.IsInsideCamera(expectedX, e.y) || !e.w.camera.IsInsideCamera(expectedX, e.y+e.height)
+			cameraX = !e.w.camera.IsInsideCamera(expectedX, e.y) || !e.w.camera.IsInsideCamera(expectedX, e.y+e.height)
 		}
 	}
-	if collisionX {
+	wasOnGround := e.onGround
+	if collisionX && !cameraX && wasOnGround && e.vy >= 0 && !e.isClimbing {
+		// Walk up slopes and small ledges
+		if step, ok := e.stepUp(); ok {
+			e.y -= step
+			collisionX = false
+		}
+	}
+	if collisionX || cameraX {
 		e.vx = 0
 	} else {
 		e.x += e.vx
@@ -134,35 +194,65 @@ func (e *Entity) Update() {
 	if e.stayWithinCamera {
 		collisionY = collisionY || !e.w.camera.IsInsideCamera(e.x, expectedY) || !e.w.camera.IsInsideCamera(e.x+e.width, expectedY)
 	}
+	e.onGround = collisionY && e.vy > 0
 	if collisionY {
 		e.vy = 0
 	} else {
 		e.y += e.vy
 	}
-
-	// TODO: Only update when theta changes
-	// e.CalcNormals()
+	if wasOnGround && !e.onGround && e.vy >= 0 && !e.isClimbing {
+		// Stay on the ground walking down slopes instead of bouncing off them
+		e.snapDown()
+	}
 
 }
 
 func (e *Entity) WillCollideWithWorld() (bool, bool) {
-	// Check collisions on left top and bottom if vx < 0, else right top and bottom
-	// Check collisions on left and right bottom if by > 0, else left and right top
-	// X and Y will stay the same, bottom Y and right X will change
-	cosTheta, sinTheta := math.Cos(e.theta), math.Sin(e.theta)
-	baseX := float64(e.x)
-	baseY := float64(e.y)
-	tl := common.NewVec2(baseX, baseY)
-	tr := common.NewVec2(baseX+float64(e.width)*cosTheta, baseY)
-	bl := common.NewVec2(baseX, baseY+float64(e.height)*cosTheta)
-	br := common.NewVec2(baseX+float64(e.width)*cosTheta-float64(e.height)*sinTheta, baseY+float64(e.height)*cosTheta+float64(e.width)*sinTheta)
-	collisionX := e.w.IsWorldCollision(float32(tl.X)+e.vx, float32(tl.Y)) || e.w.IsWorldCollision(float32(tr.X)+e.vx, float32(tr.Y)) || e.w.IsWorldCollision(float32(bl.X)+e.vx, float32(bl.Y)) || e.w.IsWorldCollision(float32(br.X)+e.vx, float32(br.Y))
-	collisionY := e.w.IsWorldCollision(float32(tl.X), float32(tl.Y)+e.vy) || e.w.IsWorldCollision(float32(tr.X), float32(tr.Y)+e.vy) || e.w.IsWorldCollision(float32(bl.X), float32(bl.Y)+e.vy) || e.w.IsWorldCollision(float32(br.X), float32(br.Y)+e.vy)
+	// Move each rotated corner along one axis at a time
+	collisionX, collisionY := false, false
+	for _, c := range e.Corners() {
+		x, y := float32(c.X), float32(c.Y)
+		collisionX = collisionX || e.w.IsWorldCollision(x+e.vx, y)
+		collisionY = collisionY || e.w.IsWorldCollision(x, y+e.vy)
+	}
 	return collisionX, collisionY
 }
 
+// Returns how far up the entity has to move to fit after moving vx, if it is at most MAXSTEPHEIGHT
+func (e *Entity) stepUp() (float32, bool) {
+	for step := float32(1); step <= MAXSTEPHEIGHT; step++ {
+		if !e.overlapsWorldAt(e.x+e.vx, e.y-step) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// Drops the entity onto ground up to MAXSTEPHEIGHT below it
+func (e *Entity) snapDown() {
+	for step := float32(1); step <= MAXSTEPHEIGHT; step++ {
+		if e.overlapsWorldAt(e.x, e.y+step) {
+			e.y += step - 1
+			e.onGround = true
+			return
+		}
+	}
+}
+
+// Returns true if the entity's center or feet overlap a ladder or vine
+func (e *Entity) IsOnClimbable() bool {
+	centerX := e.x + e.width/2
+	return e.w.IsClimbable(centerX, e.y+e.height/2) || e.w.IsClimbable(centerX, e.y+e.height-1)
+}
+
 func (e *Entity) Draw(screen *ebiten.Image) {
+	if e.Sprite == nil {
+		return
+	}
 	if !e.hasAnimation {
+		if e.im != nil {
+			drawImage(screen, e.im, e.Transform, e.w.camera)
+		}
 		return
 	}
 	op := ebiten.DrawImageOptions{}
@@ -181,6 +271,17 @@ func (e *Entity) Draw(screen *ebiten.Image) {
 	op.GeoM.Translate(float64(e.x), float64(e.y))
 	op.GeoM.Translate(float64(camOffX), float64(camOffY))
 
+	timeNow := time.Now().UnixMilli()
+	if e.IsInvulnerable() && (timeNow/BLINKMS)%2 == 0 {
+		return
+	}
+	if timeNow < e.lastHitTime+HITFLASHMS {
+		op.ColorM.Scale(1, .3, .3, 1)
+	}
+	if e.Health != nil && e.isDowned {
+		op.ColorM.Scale(.5, .5, .5, 1)
+	}
+
 	// Animation shiz
 	if e.vx != 0 {
 		e.walkAnimation.Draw(screen, &op)
@@ -201,98 +302,239 @@ type Player struct {
 	isDead bool
 	name   string
 	// TOOD: Move to gun struct
-	fireRate     int64 // Milliseconds
-	lastShotTime int64 // millseconds
+	fireRate       int64 // Milliseconds
+	lastShotTime   int64 // millseconds
+	rocketFireRate int64 // Milliseconds
+	lastRocketTime int64 // Milliseconds
+	rockets        int
+	coins          int
+	// Power ups from pickups, multiply damage and fire rate until the time in milliseconds
+	damageMultiplier, fireRateMultiplier float32
+	damageBoostUntil, rapidFireUntil     int64
+	// Milliseconds, for coyote time and jump buffering
+	lastGroundedTime, lastJumpPress int64
+	jumpHeld, isJumping             bool
+	// Side in team modes, see World.teamOf
+	team int
+	// The teammate holding revive on this player while downed, and since when in milliseconds
+	revivedBy   *Player
+	reviveStart int64
+	// Milliseconds of bleed out left when the revive started, bleeding out is paused until it stops
+	bleedOutLeft int64
 }
 
-func NewPlayer(id uint32, name string, w *World, im *ebiten.Image, pip *input.PlayerInput) *Player {
-	return &Player{
-		Entity: Entity{
-			GameObject:        *NewGameObject(id, 0, 0, TILEWIDTH-1, TILEWIDTH-1, 0, w, im, true),
-			vx:                0,
-			vy:                0,
-			stayWithinCamera:  true,
-			gravityMultiplier: 1,
-			immuneToGuns:      true,
-		},
-		pi:       pip,
-		fireRate: 100,
-		name:     name,
-		isDead:   true,
+func NewPlayer(name string, w *World, im *ebiten.Image, pip *input.PlayerInput) *Player {
+	p := &Player{
+		Entity:         *NewEntity(0, 0, TILEWIDTH-1, TILEWIDTH-1, 0, w, im, true),
+		pi:             pip,
+		fireRate:       100,
+		rocketFireRate: 1500,
+		name:           name,
+		isDead:         true,
 	}
+	p.stayWithinCamera = true
+	p.gravityMultiplier = 1
+	p.iFramesMs = PLAYERIFRAMESMS
+	p.bleedOutMs = BLEEDOUTMS
+	p.layer = PlayerLayer
+	p.mask = ZombieLayer | PickupLayer | ProjectileLayer
+	return p
 }
 
 func (p *Player) Update() {
-	if !p.pi.IsButtonPressed(input.JoyConTriggerLeft) {
-		_, xAxis := p.pi.GetAxes()
-		var magn float32 = 5
-		p.vx = magn * xAxis
-	} else {
-		p.vx = 0
+	if p.IsStunned() {
+		// Knocked back, slide to a stop
+		p.run(0)
+		return
+	}
+	yAxis, xAxis := p.pi.GetAxes()
+	if p.isDowned {
+		// Can only crawl until revived
+		p.isClimbing = false
+		p.run(xAxis * DOWNEDCRAWLMULTIPLIER)
+		return
+	}
+	if p.pi.IsButtonPressed(input.JoyConTriggerLeft) {
+		// Stand still to aim
+		xAxis = 0
+	}
+	p.run(xAxis)
+	// Grab on when pushing vertically, let go when off the ladder
+	if !p.IsOnClimbable() {
+		p.isClimbing = false
+	} else if yAxis != 0 {
+		p.isClimbing = true
 	}
-	if p.pi.IsButtonPressed(input.JoyConB) && (p.w.IsWorldCollision(p.x, p.y+p.height+2) || p.w.IsWorldCollision(p.x+p.width, p.y+p.height+2)) {
-		p.vy -= 8.5
+	if p.isClimbing {
+		p.vy = CLIMBSPEED * yAxis
 	}
+	p.jump(p.pi.IsButtonPressed(input.JoyConB))
 
 	if p.pi.IsButtonPressed(input.JoyConA) {
 		p.Shoot()
 	}
+	if p.pi.IsButtonPressed(input.JoyConX) {
+		p.ShootRocket()
+	}
+}
+
+// Unit vector the player is aiming in, defaults to facing direction
+func (p *Player) aimDir() (float32, float32) {
+	yDir, xDir := p.pi.GetAxes()
+	if math.Abs(float64(yDir)) < .05 && math.Abs(float64(xDir)) < .05 {
+		return float32(p.facingDir.X), 0
+	}
+	m := float32(math.Sqrt(float64(yDir*yDir + xDir*xDir)))
+	return xDir / m, yDir / m
 }
 
 // TOOD: Move to gun object
 func (p *Player) Shoot() {
 	curTime := time.Now().UnixMilli()
-	if p.lastShotTime < curTime-p.fireRate {
-		yDir, xDir := p.pi.GetAxes()
-		if math.Abs(float64(yDir)) < .05 && math.Abs(float64(xDir)) < .05 {
-			yDir = 0
-			xDir = float32(p.facingDir.X)
-		} else {
-			m := float32(math.Sqrt(float64(yDir*yDir + xDir*xDir)))
-			yDir /= m
-			xDir /= m
-		}
-
+	fireRate := p.fireRate
+	if curTime < p.rapidFireUntil {
+		fireRate = int64(float32(fireRate) / p.fireRateMultiplier)
+	}
+	if p.lastShotTime < curTime-fireRate {
+		xDir, yDir := p.aimDir()
 		bulletSpeed := float32(30)
 
 		p.lastShotTime = curTime
-		p := NewBullet(p.x+p.width/2, p.y+p.height/3, xDir*bulletSpeed, yDir*bulletSpeed, 25, p.w)
-		p.w.AddProjectile(p)
+		b := NewBullet(p.x+p.width/2, p.y+p.height/3, xDir*bulletSpeed, yDir*bulletSpeed, p.gunDamage(25, curTime), p.w)
+		b.owner = &p.Entity
+		p.w.AddProjectile(b)
+		p.w.events.Publish(ShotFired{p, b})
+	}
+}
+
+func (p *Player) ShootRocket() {
+	curTime := time.Now().UnixMilli()
+	if p.rockets > 0 && p.lastRocketTime < curTime-p.rocketFireRate {
+		xDir, yDir := p.aimDir()
+		rocketSpeed := float32(12)
+
+		p.lastRocketTime = curTime
+		p.rockets--
+		r := NewRocket(p.x+p.width/2, p.y+p.height/3, xDir*rocketSpeed, yDir*rocketSpeed, p.gunDamage(100, curTime), p.w)
+		r.owner = &p.Entity
+		p.w.AddProjectile(r)
+		p.w.events.Publish(ShotFired{p, r})
+	}
+}
+
+// Damage a shot does, boosted by a damage power up
+func (p *Player) gunDamage(base float32, curTime int64) float32 {
+	if curTime < p.damageBoostUntil {
+		return base * p.damageMultiplier
 	}
+	return base
 }
 
 type Projectile struct {
 	Entity
 	damage float32
+	// Explodes on impact when greater than 0
+	explosionRadius float32
+	// canHit, bound once so raycasting with it does not allocate
+	filter func(e *Entity) bool
 }
 
-func NewProjectile(id uint32, x, y, width, height, vx, vy, damage float32, w *World, im *ebiten.Image) *Projectile {
-	return &Projectile{
-		Entity: Entity{
-			GameObject:        *NewGameObject(id, x, y, width, height, math.Atan2(float64(vy), float64(vx)), w, im, false),
-			vx:                vx,
-			vy:                vy,
-			stayWithinCamera:  false,
-			health:            1,
-			collidingEntities: nil,
-			immuneToGuns:      true,
-		},
-		damage: damage,
+func NewProjectile(x, y, width, height, vx, vy, damage float32, w *World, im *ebiten.Image) *Projectile {
+	// Reset a pooled one in place, keeping its component allocations
+	p := w.projectilePool.Get()
+	*p.Transform = Transform{x, y, width, height, math.Atan2(float64(vy), float64(vx)), p.normals}
+	p.CalcNormals()
+	*p.Velocity = Velocity{vx: vx, vy: vy}
+	mask := ZombieLayer
+	if w.playersCanBeShot() {
+		mask |= PlayerLayer
 	}
+	*p.Collider = Collider{layer: ProjectileLayer, mask: mask, isTrigger: true, immuneToGuns: true, collidingEntities: p.collidingEntities[:0]}
+	*p.Health = Health{health: 1}
+	*p.Sprite = Sprite{im: im}
+	p.stayWithinCamera = false
+	p.damage = damage
+	p.explosionRadius = 0
+	return p
+}
+
+func newPooledProjectile() *Projectile {
+	p := &Projectile{Entity: *NewEntity(0, 0, 0, 0, 0, nil, nil, false)}
+	p.filter = p.canHit
+	return p
 }
 
 func NewBullet(x, y, vx, vy, damage float32, w *World) *Projectile {
-	return NewProjectile(2, x, y, 18, 4, vx, vy, damage, w, w.gdl.GetSpriteImage(graphics.Bullet))
+	return NewProjectile(x, y, 18, 4, vx, vy, damage, w, w.gdl.GetSpriteImage(graphics.Bullet))
+}
+
+// There is no rocket art in the spritesheet, so rockets are a bigger bullet
+func NewRocket(x, y, vx, vy, damage float32, w *World) *Projectile {
+	r := NewProjectile(x, y, 24, 8, vx, vy, damage, w, w.gdl.GetSpriteImage(graphics.Bullet))
+	r.explosionRadius = 2.5 * TILEWIDTH
+	return r
+}
+
+// Whether the projectile stops at e. It passes through its owner, whatever its owner fired, and anything its
+// owner's guns cannot hurt
+func (p *Projectile) canHit(e *Entity) bool {
+	if p.owner != nil && (e == p.owner || e.owner == p.owner) {
+		return false
+	}
+	return p.w.gunsCanHurt(p.owner, e)
 }
 
 func (p *Projectile) Update() {
-	xCol, yCol := p.WillCollideWithWorld()
-	p.shouldRemove = xCol || yCol
 	for _, e := range p.collidingEntities {
-		if e.immuneToGuns {
+		if !p.canHit(e) {
 			continue
 		}
-		e.health -= p.damage
-		p.shouldRemove = true
+		cx, cy := p.Center()
+		p.hit(cx, cy, nil, e)
+		return
+	}
+	// Sweep the center along this tick's movement, so fast projectiles cannot pass through thin walls or zombies
+	cx, cy := p.Center()
+	if hit, isHit := p.w.Raycast(cx, cy, cx+p.vx, cy+p.vy, p.mask, p.filter); isHit {
+		p.hit(hit.x, hit.y, hit.tile, hit.entity)
+		return
+	}
+	// A corner clipped the world even though the center did not, a glancing hit
+	if tile := p.clippedTile(); tile != nil {
+		p.hit(cx+p.vx, cy+p.vy, tile, nil)
+	}
+}
+
+// The solid tile a corner moves into this tick, checked along each axis as WillCollideWithWorld does, or nil
+func (p *Projectile) clippedTile() *Tile {
+	for _, c := range p.Corners() {
+		x, y := float32(c.X), float32(c.Y)
+		if p.w.IsWorldCollision(x+p.vx, y) {
+			return p.w.tileAt(x+p.vx, y)
+		}
+		if p.w.IsWorldCollision(x, y+p.vy) {
+			return p.w.tileAt(x, y+p.vy)
+		}
+	}
+	return nil
+}
+
+// Damages what was hit at x, y, or explodes there
+func (p *Projectile) hit(x, y float32, tile *Tile, e *Entity) {
+	p.w.Despawn(&p.Entity)
+	if p.explosionRadius > 0 {
+		p.w.Explode(p.owner, x, y, p.explosionRadius, p.damage)
+		return
+	}
+	if tile != nil {
+		tile.Damage(p.damage)
+	}
+	if e != nil {
+		var impulse common.Vec2
+		if p.vx != 0 || p.vy != 0 {
+			dir := common.Normalize(common.NewVec2(float64(p.vx), float64(p.vy)))
+			impulse = common.Scale(dir, float64(BULLETKNOCKBACK))
+		}
+		e.TakeDamage(p.owner, p.damage, impulse)
 	}
 }
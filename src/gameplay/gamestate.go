@@ -1,16 +1,29 @@
 package gameplay
 
 import (
+	"fmt"
+	"image/color"
+	"log"
+	"time"
+
 	"github.com/Jack-Craig/gogame/src/common"
 	"github.com/Jack-Craig/gogame/src/graphics"
 	"github.com/Jack-Craig/gogame/src/input"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
 )
 
 type Handler struct {
 	gdl     *graphics.GraphicsDataLoader
 	im      *input.InputManager
 	players []*Player
+	// Tiled map to play instead of a procedural level, if set
+	mapPath string
+	// Seeds the next procedural level
+	seed int64
+	// Match options picked in the menu
+	mode         GameMode
+	friendlyFire bool
 }
 
 type GameState interface {
@@ -34,7 +47,17 @@ func NewPlayState(handler Handler) *PlayState {
 }
 
 func (ps *PlayState) GetNextState() GameState {
+	if a := ps.world.arena; a != nil {
+		if a.roundOver {
+			ps.world.Close()
+			return NewScoreboardState(ps.Handler, a.results(), a.winningTeam())
+		}
+		return nil
+	}
 	if ps.world.allPlayersDoneOrDead {
+		ps.world.Close()
+		// Next level is a different one
+		ps.seed++
 		return NewShopState(ps.Handler)
 	}
 	return nil
@@ -61,10 +84,14 @@ type MenuState struct {
 	readyForNextState bool
 }
 
-func NewMenuState() *MenuState {
+func NewMenuState(mapPath string, seed int64) *MenuState {
 	ms := &MenuState{}
+	ms.mapPath = mapPath
+	ms.seed = seed
 	var pd common.PlayerDataJson
-	common.LoadJSON("res/models.json", &pd)
+	if err := common.LoadJSON("res/models.json", &pd); err != nil {
+		log.Fatal(err)
+	}
 	for name, d := range pd.Players {
 		ms.playerTileIds = append(ms.playerTileIds, struct {
 			id   uint32
@@ -88,7 +115,8 @@ func NewMenuState() *MenuState {
 func (ms *MenuState) GetNextState() GameState {
 	if ms.readyForNextState {
 		for _, data := range ms.playerData {
-			p := NewPlayer(data.id, data.name, nil, data.im, data.pi)
+			p := NewPlayer(data.name, nil, data.im, data.pi)
+			p.team = data.team
 			ms.players = append(ms.players, p)
 		}
 		return NewPlayState(ms.Handler)
@@ -114,6 +142,14 @@ func (ms *MenuState) Draw(screen *ebiten.Image) {
 	for _, pd := range ms.playerData {
 		pd.Draw(screen)
 	}
+	friendlyFire := "off"
+	if ms.friendlyFire {
+		friendlyFire = "on"
+	}
+	options := fmt.Sprintf("%s (Y)   Friendly fire %s (A)   Team (X)", ms.mode, friendlyFire)
+	font := *ms.gdl.GetFontSmall()
+	bounds := text.BoundString(font, options)
+	text.Draw(screen, options, font, ms.windowWidth/2-bounds.Size().X/2, ms.windowHeight-bounds.Size().Y, color.White)
 }
 
 // SHOPSTATE
@@ -137,3 +173,62 @@ func (ss *ShopState) Update() {
 func (ss *ShopState) Draw(screen *ebiten.Image) {
 
 }
+
+// SCOREBOARDSTATE
+type ScoreboardState struct {
+	GameState
+	Handler
+	results []arenaResult
+	// -1 for a draw
+	winningTeam int
+	// Milliseconds, input is ignored until then so nobody skips the scores by accident
+	readyAt int64
+}
+
+func NewScoreboardState(handler Handler, results []arenaResult, winningTeam int) *ScoreboardState {
+	return &ScoreboardState{
+		Handler:     handler,
+		results:     results,
+		winningTeam: winningTeam,
+		readyAt:     time.Now().UnixMilli() + SCOREBOARDDELAYMS,
+	}
+}
+
+// Any player pressing B starts a rematch
+func (ss *ScoreboardState) GetNextState() GameState {
+	if time.Now().UnixMilli() < ss.readyAt {
+		return nil
+	}
+	for _, p := range ss.players {
+		if p.pi.IsButtonPressed(input.JoyConB) {
+			return NewPlayState(ss.Handler)
+		}
+	}
+	return nil
+}
+
+func (ss *ScoreboardState) Update() {
+
+}
+
+func (ss *ScoreboardState) Draw(screen *ebiten.Image) {
+	screenWidth, screenHeight := screen.Size()
+	font := *ss.gdl.GetFontNormal()
+	centered := func(msg string, y int, clr color.Color) {
+		bounds := text.BoundString(font, msg)
+		text.Draw(screen, msg, font, screenWidth/2-bounds.Size().X/2, y, clr)
+	}
+
+	title, titleColor := "Draw", color.Color(color.Black)
+	if ss.winningTeam >= 0 {
+		title, titleColor = fmt.Sprintf("Team %d wins!", ss.winningTeam+1), teamColors[ss.winningTeam]
+	}
+	centered(title, screenHeight/6, titleColor)
+	centered("Kills  Deaths  Points", screenHeight/6+60, color.Black)
+	for i, r := range ss.results {
+		centered(fmt.Sprintf("%s   %d  %d  %d", r.name, r.kills, r.deaths, r.points), screenHeight/6+100+40*i, teamColors[r.team])
+	}
+	if time.Now().UnixMilli() >= ss.readyAt {
+		centered("B for a rematch", screenHeight-40, color.Black)
+	}
+}
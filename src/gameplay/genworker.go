@@ -0,0 +1,48 @@
+package gameplay
+
+// Takes columns from a level source on its own goroutine, running ahead of the camera so generation never
+// stalls a frame. Columns are made in order by a single goroutine, so a seeded level comes out the same
+// however the goroutines are scheduled
+type GenWorker struct {
+	// Finished columns in order, holds at most MAXWORLDGENBUFFERLEN before the worker waits
+	columns chan Column
+	done    chan struct{}
+}
+
+func NewGenWorker(level LevelSource) *GenWorker {
+	gw := &GenWorker{
+		columns: make(chan Column, MAXWORLDGENBUFFERLEN),
+		done:    make(chan struct{}),
+	}
+	go gw.run(level)
+	return gw
+}
+
+func (gw *GenWorker) run(level LevelSource) {
+	for x := uint32(0); ; x++ {
+		col := level.Column(x)
+		select {
+		case gw.columns <- col:
+		case <-gw.done:
+			return
+		}
+	}
+}
+
+// Returns the next column. If wait is false and the worker has not finished it yet, returns false
+func (gw *GenWorker) Next(wait bool) (Column, bool) {
+	if wait {
+		return <-gw.columns, true
+	}
+	select {
+	case col := <-gw.columns:
+		return col, true
+	default:
+		return Column{}, false
+	}
+}
+
+// Stops the worker, it must not be used afterwards
+func (gw *GenWorker) Stop() {
+	close(gw.done)
+}
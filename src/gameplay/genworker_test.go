@@ -0,0 +1,51 @@
+package gameplay
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/Jack-Craig/gogame/src/common"
+)
+
+// Runs the rest of the test from the repository root, where resource paths are relative to
+func chdirRoot(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.Chdir(wd)
+	})
+}
+
+func newSeededLevel(t *testing.T, seed int64) *Level {
+	var biomeData common.BiomeDataJson
+	if err := common.LoadJSON("res/world/biomes.json", &biomeData); err != nil {
+		t.Fatal(err)
+	}
+	var pickupData common.PickupDataJson
+	if err := common.LoadJSON("res/pickups.json", &pickupData); err != nil {
+		t.Fatal(err)
+	}
+	return NewLevel(100, seed, biomeData, LoadPrefabs(biomeData), pickupData.LootTables["terrain"])
+}
+
+func TestGenWorkerMatchesInPlace(t *testing.T) {
+	chdirRoot(t)
+	for _, seed := range []int64{1, 42, 1234567} {
+		inPlace := newSeededLevel(t, seed)
+		gw := NewGenWorker(newSeededLevel(t, seed))
+		for x := uint32(0); x < 2000; x++ {
+			expected := inPlace.Column(x)
+			col, _ := gw.Next(true)
+			if !reflect.DeepEqual(col, expected) {
+				t.Fatalf("seed %d: column %d from the worker differs from the one generated in place", seed, x)
+			}
+		}
+		gw.Stop()
+	}
+}
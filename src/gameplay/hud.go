@@ -0,0 +1,158 @@
+package gameplay
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/Jack-Craig/gogame/src/graphics"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Player info along the bottom of the screen, kept up to date from world events
+type HUD struct {
+	w     *World
+	stats map[*Entity]*playerStats
+}
+
+type playerStats struct {
+	health float32
+	kills  int
+	coins  int
+	isDead bool
+	// Bleed out and revive progress are read from the player while drawing
+	isDowned bool
+}
+
+func NewHUD(w *World) *HUD {
+	h := &HUD{w: w, stats: make(map[*Entity]*playerStats)}
+	Subscribe(&w.events, func(ev PlayerJoined) {
+		h.stats[&ev.player.Entity] = &playerStats{health: ev.player.health, coins: ev.player.coins}
+	})
+	Subscribe(&w.events, func(ev PickupCollected) {
+		if s, ok := h.stats[&ev.player.Entity]; ok {
+			s.health = ev.player.health
+			s.coins = ev.player.coins
+		}
+	})
+	Subscribe(&w.events, func(ev EntityDamaged) {
+		if s, ok := h.stats[ev.target]; ok {
+			s.health = ev.target.health
+		}
+	})
+	Subscribe(&w.events, func(ev EntityDowned) {
+		if s, ok := h.stats[ev.target]; ok {
+			s.health = 0
+			s.isDowned = true
+		}
+	})
+	Subscribe(&w.events, func(ev PlayerRevived) {
+		if s, ok := h.stats[&ev.player.Entity]; ok {
+			s.health = ev.player.health
+			s.isDowned = false
+		}
+	})
+	Subscribe(&w.events, func(ev PlayerRespawned) {
+		if s, ok := h.stats[&ev.player.Entity]; ok {
+			s.health = ev.player.health
+			s.isDead = false
+		}
+	})
+	Subscribe(&w.events, func(ev EntityKilled) {
+		if s, ok := h.stats[ev.target]; ok {
+			s.health = 0
+			s.isDead = true
+			s.isDowned = false
+		}
+		if s, ok := h.stats[ev.source]; ok && h.w.creditsKill(ev.source, ev.target) {
+			s.kills++
+		}
+	})
+	return h
+}
+
+func (h *HUD) Draw(screen *ebiten.Image) {
+	for x, player := range h.w.players {
+		if s, ok := h.stats[&player.Entity]; ok {
+			h.drawPlayerInfo(x+1, player, s, screen)
+		}
+	}
+	if h.w.mode == RaceMode && h.w.winningTeam >= 0 {
+		h.drawBanner(screen, fmt.Sprintf("Team %d wins the race!", h.w.winningTeam+1), teamColors[h.w.winningTeam])
+	}
+	if a := h.w.arena; a != nil {
+		scores := a.teamScores()
+		msg := fmt.Sprintf("Team 1  %d - %d  Team 2", scores[0], scores[1])
+		if left := a.timeLeft(time.Now().UnixMilli()); a.data.TimeLimitMs > 0 {
+			msg += fmt.Sprintf("   %d:%02d", left/60000, left/1000%60)
+		}
+		h.drawBanner(screen, msg, color.White)
+	}
+}
+
+// Centred text along the top of the screen
+func (h *HUD) drawBanner(screen *ebiten.Image, msg string, clr color.Color) {
+	f := *h.w.gdl.GetFontNormal()
+	bounds := text.BoundString(f, msg)
+	text.Draw(screen, msg, f, int(h.w.camera.screenWidth)/2-bounds.Size().X/2, bounds.Size().Y*2, clr)
+}
+
+func (h *HUD) drawPlayerInfo(x int, player *Player, s *playerStats, screen *ebiten.Image) {
+	w := h.w
+	renderY := int(w.camera.screenHeight)
+	renderX := int(w.camera.screenWidth * (float32(x) / float32(len(w.players)+1)))
+
+	// Render player info background thing
+	bo := w.gdl.GetSpriteImage(graphics.PlayerInfo)
+	width, height := bo.Size()
+	scale := int(TILEWIDTH*4) / width
+	realWidth := width * scale
+	realHeight := height * scale
+	boxOp := ebiten.DrawImageOptions{}
+	boxOp.GeoM.Scale(float64(scale), float64(scale))
+	boxOp.GeoM.Translate(float64(renderX-realWidth/2), float64(renderY-realHeight))
+	screen.DrawImage(bo, &boxOp)
+
+	// Render player name, kills and coins, health
+	statusText := fmt.Sprintf("%0.f", s.health)
+	if s.isDowned {
+		statusText = "DOWN"
+	}
+	boxSize := text.BoundString(*w.gdl.GetFontNormal(), statusText)
+	textWidth := boxSize.Size().X
+	textHeight := boxSize.Size().Y
+	f := w.gdl.GetFontNormal()
+	var nameColor color.Color = color.White
+	if w.mode != CoopMode {
+		nameColor = teamColors[player.team]
+	}
+	text.Draw(screen, fmt.Sprintf("%s  %d  $%d", player.name, s.kills, s.coins), *w.gdl.GetFontSmall(), renderX-textWidth/2, renderY-textHeight/2-24, nameColor)
+	text.Draw(screen, statusText, *f, renderX-textWidth/2, renderY-textHeight/2, color.White)
+
+	// Render bleed out and revive bars above the box while downed
+	if s.isDowned {
+		timeNow := time.Now().UnixMilli()
+		barX := float64(renderX - realWidth/2)
+		barY := float64(renderY - realHeight - 12)
+		barWidth := float64(realWidth)
+		ebitenutil.DrawRect(screen, barX, barY, barWidth, 4, color.Gray{0x40})
+		ebitenutil.DrawRect(screen, barX, barY, barWidth*float64(player.bleedOutFraction(timeNow)), 4, color.RGBA{0xd0, 0x20, 0x20, 0xff})
+		if progress := player.reviveProgress(timeNow); progress > 0 {
+			ebitenutil.DrawRect(screen, barX, barY+6, barWidth*float64(progress), 4, color.RGBA{0x20, 0xd0, 0x40, 0xff})
+		}
+	}
+
+	// Render tiny player (or skull)
+	op := ebiten.DrawImageOptions{}
+	guyScale := 1.2 * float64(height) / float64(graphics.TILESIZE)
+	guySize := guyScale * float64(graphics.TILESIZE)
+	op.GeoM.Scale(guyScale, guyScale)
+	op.GeoM.Translate(float64(renderX)-guySize-float64(textWidth)/2-10, float64(renderY-textHeight)-guySize/2)
+	if s.isDead {
+		screen.DrawImage(w.gdl.GetSpriteImage(graphics.Skull), &op)
+	} else {
+		screen.DrawImage(player.im, &op)
+	}
+}
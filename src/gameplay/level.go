@@ -0,0 +1,187 @@
+package gameplay
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/Jack-Craig/gogame/src/common"
+	"github.com/Jack-Craig/gogame/src/graphics"
+	"github.com/aquilax/go-perlin"
+)
+
+// Starts with entrance, ends with exit. Collection of biomes
+type Level struct {
+	perlin *perlin.Perlin
+	// All generation randomness comes from here, so a seed always makes the same level
+	rng *rand.Rand
+	// Width of world in array coordinates
+	worldWidth uint32
+	// In array coordinates, the next column to generate. Does not wrap
+	worldXGen uint32
+	// Every biome so far in order, kept so the camera can backtrack through them
+	biomes      []Biome
+	curBiomeIdx int
+	// Generation runs on the GenWorker, guards biomes and curBiomeIdx against BlendAt on the main loop
+	biomesMu  sync.RWMutex
+	biomeData common.BiomeDataJson
+	// Ground height of the two most recently generated columns
+	lastGroundY, prevGroundY uint32
+	// The most recently generated column came from a hand made chunk
+	lastAuthored bool
+	// Run in order over every generated column
+	passes []GenPass
+	// Hand made chunks by name, and the one currently being placed
+	prefabs       map[string]*Prefab
+	prefab        *Prefab
+	prefabStartX  uint32
+	prefabOffsetY int
+	// Pickups left lying on generated ground
+	terrainLoot common.LootTableJson
+	// Generated but not yet handed out, the next two columns can still add ladders or slopes to these
+	pending []Column
+}
+
+func NewLevel(worldWidth uint32, seed int64, biomeData common.BiomeDataJson, prefabs map[string]*Prefab, terrainLoot common.LootTableJson) *Level {
+	rng := rand.New(rand.NewSource(seed))
+	l := &Level{
+		worldWidth:  worldWidth,
+		rng:         rng,
+		perlin:      perlin.NewPerlin(2, 2, 3, rng.Int63()),
+		curBiomeIdx: 0,
+		biomeData:   biomeData,
+		lastGroundY: LEVELHEIGHT / 2,
+		passes:      DefaultGenPasses(),
+		prefabs:     prefabs,
+		terrainLoot: terrainLoot,
+	}
+	l.biomes = make([]Biome, 1)
+	start := l.biomeData.Biomes["start"]
+	l.biomes[0].start("start", start, 0, LEVELHEIGHT/2, nil, l.rng)
+	return l
+}
+
+func (l *Level) Column(x uint32) Column {
+	for l.worldXGen <= x+2 {
+		l.generateColumn()
+	}
+	col := l.pending[0]
+	l.pending = l.pending[1:]
+	return col
+}
+
+func (l *Level) Update(worldXStart, worldXEnd uint32) []SpawnEvent {
+	return nil
+}
+
+func (l *Level) Width() uint32 {
+	return l.worldWidth
+}
+
+func (l *Level) Height() uint32 {
+	return LEVELHEIGHT
+}
+
+func (l *Level) PlayerStart() (float32, float32) {
+	return PLAYERWORLDSTARTX, PLAYERWORLDSTARTY
+}
+
+func (l *Level) IsComplete(worldXEnd uint32, furthestPlayerX float32) bool {
+	return worldXEnd >= l.worldWidth
+}
+
+func (l *Level) generateColumn() {
+	// Check for current biome, and if we need to make a new one
+	curBiome := &l.biomes[l.curBiomeIdx]
+	if l.prefab == nil && curBiome.startX+curBiome.length <= l.worldXGen {
+		// This biome has finished being generated! Maybe a hand made chunk before the next one
+		if p := l.rollPrefab(curBiome); p != nil {
+			l.startPrefab(p)
+		} else {
+			curBiome = l.nextBiome(curBiome.floorHeight, curBiome)
+		}
+	}
+	// Generate terrain
+	gen := GenColumn{x: l.worldXGen}
+	if l.prefab != nil {
+		l.prefab.fillColumn(l.worldXGen-l.prefabStartX, l.prefabOffsetY, &gen)
+	} else {
+		for _, pass := range l.passes {
+			pass.Apply(l, curBiome, &gen)
+		}
+	}
+	l.pending = append(l.pending, l.themeColumn(curBiome, &gen))
+	if l.prefab != nil && l.worldXGen+1 >= l.prefabStartX+l.prefab.width {
+		// Pick up from the chunk's right edge, with the heightmap shifted so the next column is level with its floor
+		b := l.nextBiome(l.prefab.rightFloorY(l.prefabOffsetY), nil)
+		b.baseHeight -= sampleHeight(l, &b.BiomeJson, 0, l.worldXGen+1)
+		l.prefab = nil
+	}
+	l.worldXGen++
+}
+
+// Moves on to the next biome, picked from the current one's neighbours
+func (l *Level) nextBiome(floorHeight uint32, blendFrom *Biome) *Biome {
+	curBiome := &l.biomes[l.curBiomeIdx]
+	newType := curBiome.pickNext(l.rng)
+	l.biomesMu.Lock()
+	defer l.biomesMu.Unlock()
+	l.biomes = append(l.biomes, Biome{})
+	l.curBiomeIdx++
+	newCur := &l.biomes[l.curBiomeIdx]
+	newCur.start(newType, l.biomeData.Biomes[newType], l.worldXGen, floorHeight, blendFrom, l.rng)
+	return newCur
+}
+
+// Turns a generated column into tiles themed by biome b
+func (l *Level) themeColumn(b *Biome, gen *GenColumn) Column {
+	col := Column{x: gen.x, tiles: make([]TileSpec, LEVELHEIGHT), spawns: gen.spawns}
+	groundY := gen.groundY
+	climbable := TileSpec{sprite: graphics.SpriteID(b.ClimbTile), hasSprite: true, isPassable: true, isClimbable: true}
+
+	for y := uint32(0); y < LEVELHEIGHT; y++ {
+		switch gen.tiles[y] {
+		case SurfaceTile:
+			col.tiles[y] = TileSpec{sprite: graphics.SpriteID(b.SurfaceTile), hasSprite: true, health: b.TileHealth, material: b.Material}
+		case SubsurfaceTile:
+			col.tiles[y] = TileSpec{sprite: graphics.SpriteID(b.SubsurfaceTile), hasSprite: true, health: b.TileHealth, material: b.Material}
+		case ClimbableTile:
+			col.tiles[y] = climbable
+		default:
+			col.tiles[y] = airSpec
+		}
+	}
+	// Cliff faces get a ladder or vine on their open side
+	if gen.x > 0 && groundY+MINCLIMBABLECLIFF <= l.lastGroundY && len(l.pending) > 0 {
+		// Rising cliff, climb from the previous column
+		placeClimbable(&l.pending[len(l.pending)-1], groundY, l.lastGroundY, climbable)
+	} else if groundY >= l.lastGroundY+MINCLIMBABLECLIFF {
+		// Falling cliff, climb from this column
+		placeClimbable(&col, l.lastGroundY, groundY, climbable)
+	}
+	l.placeSlopes(b, &col, groundY, gen.authored)
+	l.prevGroundY = l.lastGroundY
+	l.lastGroundY = groundY
+	l.lastAuthored = gen.authored
+
+	// Maybe zombie? Authored chunks place their own
+	if !gen.authored && l.rng.Intn(10) < 1 {
+		col.spawns = append(col.spawns, SpawnEvent{ZombieSpawn, float32(gen.x) * TILEWIDTH, float32(groundY)*TILEWIDTH - TILEWIDTH, "", nil})
+	}
+	// Maybe something lying on the ground?
+	if !gen.authored && l.rng.Float64() < l.terrainLoot.DropChance {
+		if item := rollLoot(l.terrainLoot.Items, l.rng); item != "" {
+			col.spawns = append(col.spawns, SpawnEvent{PickupSpawn, float32(gen.x) * TILEWIDTH, float32(groundY)*TILEWIDTH - TILEWIDTH, item, nil})
+		}
+	}
+	return col
+}
+
+// Makes the open tiles in col from yStart up to (not including) yEnd climbable
+func placeClimbable(col *Column, yStart, yEnd uint32, climbable TileSpec) {
+	for y := yStart; y < yEnd && y < uint32(len(col.tiles)); y++ {
+		if !col.tiles[y].isPassable {
+			continue
+		}
+		col.tiles[y] = climbable
+	}
+}
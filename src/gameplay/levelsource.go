@@ -0,0 +1,144 @@
+package gameplay
+
+import (
+	"github.com/Jack-Craig/gogame/src/common"
+	"github.com/Jack-Craig/gogame/src/graphics"
+)
+
+// Anything that can fill the world: procedural Levels, Tiled maps, or fixed layouts for tests.
+// World owns the tile chunks and asks its source for columns as the camera moves
+type LevelSource interface {
+	// Returns column x, in array coordinates. Called once for every column, in order, from the GenWorker
+	// goroutine; every other method is called from the main loop
+	Column(x uint32) Column
+	// Spawns not tied to a column, such as timed spawners. Called every tick with the visible range in array coordinates
+	Update(worldXStart, worldXEnd uint32) []SpawnEvent
+	// In array coordinates, the camera stops at this edge
+	Width() uint32
+	// In tiles, every column has this many
+	Height() uint32
+	PlayerStart() (float32, float32)
+	// True once players may walk off the right of the screen
+	IsComplete(worldXEnd uint32, furthestPlayerX float32) bool
+	// Biome settings being faded out of and into at world x, and how far into the fade it is
+	BlendAt(worldX float32) (*common.BiomeJson, *common.BiomeJson, float64)
+	// Name of the biome at world x
+	BiomeAt(worldX float32) string
+}
+
+// Everything needed to build one tile, without touching any images
+type TileSpec struct {
+	sprite                  graphics.SpriteID
+	hasSprite               bool
+	isPassable, isClimbable bool
+	// 0 for unbreakable
+	health float32
+	shape  TileShape
+	// Key into the movement config's materials, "default" if empty
+	material string
+}
+
+var airSpec = TileSpec{isPassable: true}
+
+// One column of tiles handed from a level source to the world, from the top of the level down
+type Column struct {
+	// In array coordinates
+	x      uint32
+	tiles  []TileSpec
+	spawns []SpawnEvent
+}
+
+func airColumn(x, height uint32) Column {
+	col := Column{x: x, tiles: make([]TileSpec, height)}
+	for y := range col.tiles {
+		col.tiles[y] = airSpec
+	}
+	return col
+}
+
+type SpawnKind uint8
+
+const (
+	ZombieSpawn SpawnKind = iota
+	PickupSpawn
+)
+
+// Something for the world to create, x and y in world coordinates
+type SpawnEvent struct {
+	kind SpawnKind
+	x, y float32
+	// Which pickup, rolled when spawned if empty
+	item string
+	// Told when the zombie spawned leaves the world, may be nil
+	spawner Spawner
+}
+
+// Keeps count of the zombies it spawned
+type Spawner interface {
+	Despawned()
+}
+
+// Follows the camera, taking columns from the level ahead of it and loading the chunks around it
+func (w *World) updateTiles() {
+	offX, _ := w.camera.GetRenderOffset()
+	w.worldXStart = uint32(-offX / TILEWIDTH)
+	w.worldXEnd = w.worldXStart + uint32(w.camera.screenWidth/TILEWIDTH)
+
+	var furthestPlayerX float32
+	w.inputs.Each(func(id EntityID, player *Player) {
+		if player.x > furthestPlayerX {
+			furthestPlayerX = player.x
+		}
+	})
+	if w.level.IsComplete(w.worldXEnd, furthestPlayerX) {
+		w.canLeave = true
+	}
+
+	// Install what the worker has ready up to MAXWORLDGENBUFFERLEN ahead, only waiting on it if we are within MINWORLDGENBUFFERLEN of the generated section
+	for w.worldXStart+MAXWORLDGENBUFFERLEN >= w.worldXGen {
+		col, ok := w.gen.Next(w.worldXStart+MINWORLDGENBUFFERLEN >= w.worldXGen)
+		if !ok {
+			break
+		}
+		w.installColumn(col)
+		w.worldXGen++
+	}
+	w.updateChunks()
+	for _, spawn := range w.level.Update(w.worldXStart, w.worldXEnd) {
+		w.spawn(spawn)
+	}
+}
+
+// Stores a column from the level. Its spawns wait until their chunk is first loaded
+func (w *World) installColumn(col Column) {
+	gx := int32(col.x)
+	for y, spec := range col.tiles {
+		coord, lx, ly := toChunk(gx, int32(y))
+		data := w.chunkDataAt(coord)
+		data.tiles[ly][lx] = spec
+		data.damage[ly][lx] = 0
+		if chunk := w.chunks[coord]; chunk != nil {
+			chunk.tiles[ly][lx].SetSpec(spec)
+		}
+	}
+	for _, spawn := range col.spawns {
+		coord, _, _ := toChunk(toGrid(spawn.x, spawn.y))
+		if w.chunks[coord] != nil {
+			w.spawn(spawn)
+			continue
+		}
+		data := w.chunkDataAt(coord)
+		data.spawns = append(data.spawns, spawn)
+	}
+}
+
+func (w *World) spawn(s SpawnEvent) {
+	switch s.kind {
+	case ZombieSpawn:
+		z := NewBaseZombie(s.x, s.y, w)
+		z.spawnedBy = s.spawner
+		w.AddZombie(z)
+	case PickupSpawn:
+		w.spawnPickup(s.item, s.x, s.y)
+	}
+}
@@ -0,0 +1,124 @@
+package gameplay
+
+// An entity waiting to join the world at the end of the tick
+type pendingSpawn struct {
+	e *Entity
+	// Files the components only this kind of entity has, from owner, the struct embedding e. May be nil.
+	// Not a closure so spawning does not allocate
+	add   func(w *World, id EntityID, owner any)
+	owner any
+}
+
+// Calls fn with every entity as it joins the world
+func (w *World) OnSpawn(fn func(e *Entity)) {
+	w.onSpawn = append(w.onSpawn, fn)
+}
+
+// Calls fn with every entity as it leaves the world, while its components can still be read
+func (w *World) OnDespawn(fn func(e *Entity)) {
+	w.onDespawn = append(w.onDespawn, fn)
+}
+
+// Gives e an id straight away, but it only joins the world at the end of the tick
+func (w *World) AddEntity(e *Entity) EntityID {
+	return w.queueSpawn(e, nil, nil)
+}
+
+func (w *World) AddPlayer(p *Player) {
+	w.queueSpawn(&p.Entity, addPlayer, p)
+}
+
+func (w *World) AddZombie(z *Zombie) {
+	w.queueSpawn(&z.Entity, addZombie, z)
+}
+
+func (w *World) AddProjectile(b *Projectile) {
+	w.queueSpawn(&b.Entity, addProjectile, b)
+}
+
+func (w *World) AddPickup(p *Pickup) {
+	w.queueSpawn(&p.Entity, addPickup, p)
+}
+
+func addPlayer(w *World, id EntityID, owner any) {
+	w.inputs.Add(id, owner.(*Player))
+}
+
+func addZombie(w *World, id EntityID, owner any) {
+	z := owner.(*Zombie)
+	w.ais.Add(id, z.zai)
+	w.loot.Add(id, z.lootTable)
+	if z.spawnedBy != nil {
+		w.spawners.Add(id, z.spawnedBy)
+	}
+}
+
+func addProjectile(w *World, id EntityID, owner any) {
+	w.projectiles.Add(id, owner.(*Projectile))
+}
+
+func addPickup(w *World, id EntityID, owner any) {
+	w.pickups.Add(id, owner.(*Pickup))
+}
+
+func (w *World) queueSpawn(e *Entity, add func(w *World, id EntityID, owner any), owner any) EntityID {
+	e.w = w
+	e.shouldRemove = false
+	e.id = w.registry.Create()
+	w.spawnQueue = append(w.spawnQueue, pendingSpawn{e, add, owner})
+	return e.id
+}
+
+// Takes e out of the world at the end of the tick. Systems skip it for the rest of this one
+func (w *World) Despawn(e *Entity) {
+	if e.shouldRemove {
+		return
+	}
+	e.shouldRemove = true
+	w.despawnQueue = append(w.despawnQueue, e)
+}
+
+// Applies this tick's spawns then despawns, so something spawned and despawned in one tick still gets both hooks
+func (w *World) applyLifecycle() {
+	// Hooks may spawn or despawn more, which waits for the next tick. Last tick's queues are reused for it
+	spawns, despawns := w.spawnQueue, w.despawnQueue
+	w.spawnQueue, w.despawnQueue = w.spareSpawns[:0], w.spareDespawns[:0]
+	for _, s := range spawns {
+		w.fileComponents(s.e)
+		if s.add != nil {
+			s.add(w, s.e.id, s.owner)
+		}
+		for _, fn := range w.onSpawn {
+			fn(s.e)
+		}
+	}
+	for _, e := range despawns {
+		for _, fn := range w.onDespawn {
+			fn(e)
+		}
+		w.registry.Destroy(e.id)
+	}
+	w.registry.Compact()
+	clear(spawns)
+	clear(despawns)
+	w.spareSpawns, w.spareDespawns = spawns, despawns
+}
+
+func (w *World) fileComponents(e *Entity) {
+	w.entities.Add(e.id, e)
+	if e.Transform != nil {
+		w.transforms.Add(e.id, e.Transform)
+	}
+	if e.Velocity != nil {
+		w.velocities.Add(e.id, e.Velocity)
+	}
+	if e.Collider != nil {
+		w.colliders.Add(e.id, e.Collider)
+	}
+	if e.Health != nil {
+		w.healths.Add(e.id, e.Health)
+	}
+	if e.Sprite != nil {
+		w.sprites.Add(e.id, e.Sprite)
+	}
+}
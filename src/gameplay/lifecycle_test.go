@@ -0,0 +1,109 @@
+package gameplay
+
+import (
+	"slices"
+	"testing"
+)
+
+// Just the stores lifecycle and the entity systems need, without a level or graphics
+func newTestWorld() *World {
+	w := &World{}
+	w.entities = NewStore[*Entity](&w.registry)
+	w.transforms = NewStore[*Transform](&w.registry)
+	w.velocities = NewStore[*Velocity](&w.registry)
+	w.colliders = NewStore[*Collider](&w.registry)
+	w.healths = NewStore[*Health](&w.registry)
+	w.sprites = NewStore[*Sprite](&w.registry)
+	w.rayIndexTick = -1
+	return w
+}
+
+// Runs systems then applies the lifecycle, as World.Update does
+func runTick(w *World, systems ...System) {
+	w.tick++
+	for _, system := range systems {
+		system.Update(w)
+	}
+	w.events.Flush()
+	w.applyLifecycle()
+}
+
+// Despawns victim when it gets to it, recording every entity it visits
+type despawnSystem struct {
+	victim  *Entity
+	visited []EntityID
+}
+
+func (s *despawnSystem) Update(w *World) {
+	w.healths.Each(func(id EntityID, h *Health) {
+		s.visited = append(s.visited, id)
+		if id == s.victim.id {
+			w.Despawn(s.victim)
+		}
+	})
+}
+
+// Records every live entity it updates, like the real systems skipping anything leaving the world
+type recordSystem struct {
+	updated []EntityID
+}
+
+func (s *recordSystem) Update(w *World) {
+	w.velocities.Each(func(id EntityID, v *Velocity) {
+		if _, ok := w.liveEntity(id); ok {
+			s.updated = append(s.updated, id)
+		}
+	})
+}
+
+func TestDespawnDuringEach(t *testing.T) {
+	w := newTestWorld()
+	var ids []EntityID
+	for i := 0; i < 5; i++ {
+		ids = append(ids, w.AddEntity(NewEntity(float32(i)*TILEWIDTH, 0, TILEWIDTH, TILEWIDTH, 0, nil, nil, false)))
+	}
+	runTick(w)
+	victim, _ := w.entities.Get(ids[2])
+
+	despawn, record := &despawnSystem{victim: victim}, &recordSystem{}
+	runTick(w, despawn, record)
+	if !slices.Equal(despawn.visited, ids) {
+		t.Errorf("despawning mid Each visited %v, expected %v", despawn.visited, ids)
+	}
+	expected := slices.Delete(slices.Clone(ids), 2, 3)
+	if !slices.Equal(record.updated, expected) {
+		t.Errorf("later system updated %v, expected %v", record.updated, expected)
+	}
+	if _, ok := w.entities.Get(victim.id); ok {
+		t.Errorf("entity %d still in the world after the tick", victim.id)
+	}
+	for _, id := range expected {
+		if _, ok := w.healths.Get(id); !ok {
+			t.Errorf("entity %d lost its health component", id)
+		}
+	}
+}
+
+func TestSpawnAndDespawnInOneTick(t *testing.T) {
+	w := newTestWorld()
+	var hooks []string
+	w.OnSpawn(func(e *Entity) {
+		hooks = append(hooks, "spawn")
+	})
+	w.OnDespawn(func(e *Entity) {
+		if _, ok := w.entities.Get(e.id); !ok {
+			t.Errorf("entity %d components gone before OnDespawn", e.id)
+		}
+		hooks = append(hooks, "despawn")
+	})
+	e := NewEntity(0, 0, TILEWIDTH, TILEWIDTH, 0, nil, nil, false)
+	w.AddEntity(e)
+	w.Despawn(e)
+	runTick(w)
+	if !slices.Equal(hooks, []string{"spawn", "despawn"}) {
+		t.Errorf("hooks ran %v, expected spawn then despawn", hooks)
+	}
+	if _, ok := w.entities.Get(e.id); ok {
+		t.Errorf("entity %d still in the world after the tick", e.id)
+	}
+}
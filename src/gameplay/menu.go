@@ -1,6 +1,7 @@
 package gameplay
 
 import (
+	"fmt"
 	"image/color"
 	"math/rand"
 	"time"
@@ -20,11 +21,13 @@ type PlayerData struct {
 	color         color.Color
 	pi            *input.PlayerInput
 	curIdx        int
+	team          int
 	readyForStart bool
 	// Stuff for changing idx
-	lastChange      int64
-	lastChangeStart int64
-	changeDelayMs   int64
+	lastChange       int64
+	lastChangeStart  int64
+	lastOptionChange int64
+	changeDelayMs    int64
 }
 
 func NewPlayerData(id uint32, ms *MenuState) *PlayerData {
@@ -36,6 +39,7 @@ func NewPlayerData(id uint32, ms *MenuState) *PlayerData {
 		name:          ms.playerTileIds[0].name,
 		color:         color.RGBA{r + 100, g + 100, b + 100, 255},
 		pi:            (*ms.im.GetPlayerInputs())[id],
+		team:          int(id) % NUMTEAMS,
 		changeDelayMs: 300,
 	}
 }
@@ -66,6 +70,11 @@ func (pd *PlayerData) Draw(screen *ebiten.Image) {
 	boundRect := text.BoundString(font, pd.name)
 	boundRectW, boundRectH := boundRect.Size().X, boundRect.Size().Y
 	text.Draw(screen, pd.name, font, int(pd.id)*w+int(float64(w)/2)-boundRectW/2, int(float64(h/2)+.5*(guyWidth))+boundRectH, color.White)
+	if pd.ms.mode != CoopMode {
+		teamText := fmt.Sprintf("Team %d", pd.team+1)
+		teamRect := text.BoundString(font, teamText)
+		text.Draw(screen, teamText, font, int(pd.id)*w+int(float64(w)/2)-teamRect.Size().X/2, int(float64(h/2)+.5*(guyWidth))+boundRectH*3, teamColors[pd.team])
+	}
 
 	if pd.readyForStart {
 		text.Draw(screen, "Ready", font, int(pd.id)*w, 20, color.White)
@@ -99,4 +108,19 @@ func (pd *PlayerData) Update() {
 			pd.lastChangeStart = timeNow
 		}
 	}
+	// Match options, locked in once ready
+	if pd.readyForStart || pd.changeDelayMs >= timeNow-pd.lastOptionChange {
+		return
+	}
+	switch {
+	case pd.pi.IsButtonPressed(input.JoyConX):
+		pd.team = (pd.team + 1) % NUMTEAMS
+	case pd.pi.IsButtonPressed(input.JoyConY):
+		pd.ms.mode = (pd.ms.mode + 1) % numGameModes
+	case pd.pi.IsButtonPressed(input.JoyConA):
+		pd.ms.friendlyFire = !pd.ms.friendlyFire
+	default:
+		return
+	}
+	pd.lastOptionChange = timeNow
 }
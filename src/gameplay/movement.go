@@ -0,0 +1,93 @@
+package gameplay
+
+import (
+	"time"
+
+	"github.com/Jack-Craig/gogame/src/common"
+)
+
+// Accelerates towards running at axis times the top speed, with grip from the ground underfoot
+func (p *Player) run(axis float32) {
+	m := &p.w.movement
+	mat := p.w.MaterialUnder(&p.Entity)
+	target := axis * m.MaxRunSpeed * mat.SpeedMultiplier
+	speedingUp := target != 0 && (p.vx == 0 || (target > 0) == (p.vx > 0)) && abs32(target) > abs32(p.vx)
+	var rate float32
+	switch {
+	case p.onGround && speedingUp:
+		rate = m.GroundAccel * mat.Friction
+	case p.onGround:
+		rate = m.GroundDecel * mat.Friction
+	case speedingUp:
+		rate = m.AirAccel
+	default:
+		rate = m.AirDecel
+	}
+	p.vx = approach(p.vx, target, rate)
+}
+
+// Jumps with coyote time and buffering, cutting the jump short if the button is let go while rising
+func (p *Player) jump(pressed bool) {
+	m := &p.w.movement
+	timeNow := time.Now().UnixMilli()
+	if p.onGround || p.isClimbing {
+		p.lastGroundedTime = timeNow
+	}
+	if pressed && !p.jumpHeld {
+		p.lastJumpPress = timeNow
+	}
+	p.jumpHeld = pressed
+
+	buffered := p.lastJumpPress != 0 && timeNow-p.lastJumpPress <= m.JumpBufferMs
+	canJump := p.lastGroundedTime != 0 && timeNow-p.lastGroundedTime <= m.CoyoteTimeMs
+	if buffered && canJump {
+		p.isClimbing = false
+		p.onGround = false
+		p.isJumping = true
+		p.vy = -m.JumpSpeed
+		p.lastJumpPress = 0
+		p.lastGroundedTime = 0
+		return
+	}
+	if p.isJumping && !pressed && p.vy < 0 {
+		p.vy *= m.JumpReleaseMultiplier
+		p.isJumping = false
+	}
+	if p.vy >= 0 {
+		p.isJumping = false
+	}
+}
+
+// Material of the tile under the entity's feet, or the default in the air
+func (w *World) MaterialUnder(e *Entity) common.MaterialJson {
+	cx, _ := e.Center()
+	if tile := w.tileAt(cx, e.y+e.height+1); tile != nil && !tile.isPassable {
+		if mat, ok := w.movement.Materials[tile.material]; ok {
+			return mat
+		}
+	}
+	return w.movement.Materials["default"]
+}
+
+// Moves v towards target by at most rate
+func approach(v, target, rate float32) float32 {
+	if v < target {
+		v += rate
+		if v > target {
+			v = target
+		}
+	} else if v > target {
+		v -= rate
+		if v < target {
+			v = target
+		}
+	}
+	return v
+}
+
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,61 @@
+package gameplay
+
+import (
+	"image"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	// Ticks a debris particle lives for
+	DEBRISLIFETIME int = 45
+)
+
+// Particles are short lived, purely visual game objects that ignore collision
+type Particle struct {
+	GameObject
+	vx, vy, spin float32
+	ttl          int
+}
+
+func NewParticle(x, y, size, vx, vy float32, ttl int, w *World, im *ebiten.Image) *Particle {
+	p := w.particlePool.Get()
+	*p = Particle{
+		GameObject: GameObject{0, Transform{x, y, size, size, 0, nil}, im, w, false, false},
+		vx:         vx,
+		vy:         vy,
+		spin:       (rand.Float32() - .5) * .4,
+		ttl:        ttl,
+	}
+	return p
+}
+
+func (p *Particle) Update() {
+	p.vy += p.w.gravity
+	p.x += p.vx
+	p.y += p.vy
+	p.theta += float64(p.spin)
+	p.ttl--
+	if p.ttl <= 0 {
+		p.shouldRemove = true
+	}
+}
+
+// Breaks the tile's image into quarters and throws them out from its center
+func (w *World) SpawnDebris(t *Tile) {
+	if t.im == nil {
+		return
+	}
+	bounds := t.im.Bounds()
+	halfW, halfH := bounds.Dx()/2, bounds.Dy()/2
+	for i := 0; i < 4; i++ {
+		qx, qy := i%2, i/2
+		rect := image.Rect(bounds.Min.X+qx*halfW, bounds.Min.Y+qy*halfH, bounds.Min.X+(qx+1)*halfW, bounds.Min.Y+(qy+1)*halfH)
+		im := t.im.SubImage(rect).(*ebiten.Image)
+		size := t.width / 2
+		vx := (float32(qx) - .5) * (2 + rand.Float32()*2)
+		vy := -2 - rand.Float32()*3
+		w.particles = append(w.particles, NewParticle(t.x+float32(qx)*size, t.y+float32(qy)*size, size, vx, vy, DEBRISLIFETIME, w, im))
+	}
+}
@@ -0,0 +1,129 @@
+package gameplay
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/Jack-Craig/gogame/src/common"
+	"github.com/Jack-Craig/gogame/src/graphics"
+)
+
+// Items lying in the world, collected by walking into them
+type Pickup struct {
+	Entity
+	name string
+	data common.PickupJson
+}
+
+// A pickup falling from x, y, the top left of the tile it was placed in
+func NewPickup(name string, data common.PickupJson, x, y float32, w *World) *Pickup {
+	size := TILEWIDTH * 3 / 4
+	im := w.gdl.GetSpriteImage(graphics.SpriteID(data.Sprite))
+	p := &Pickup{
+		Entity: *NewEntity(x+(TILEWIDTH-size)/2, y+TILEWIDTH-size, size, size, 0, w, im, false),
+		name:   name,
+		data:   data,
+	}
+	p.gravityMultiplier = 1
+	p.health = 1
+	p.immuneToGuns = true
+	p.layer = PickupLayer
+	p.mask = PlayerLayer
+	p.isTrigger = true
+	return p
+}
+
+// Places pickup name at x, y, or one rolled from the terrain loot table if name is empty. Unknown names place nothing
+func (w *World) spawnPickup(name string, x, y float32) {
+	if name == "" {
+		name = rollLoot(w.pickupData.LootTables["terrain"].Items, nil)
+	}
+	data, ok := w.pickupData.Pickups[name]
+	if !ok {
+		return
+	}
+	w.AddPickup(NewPickup(name, data, x, y, w))
+}
+
+// Maybe drops something from loot table name at x, y
+func (w *World) dropLoot(name string, x, y float32) {
+	table, ok := w.pickupData.LootTables[name]
+	if !ok || rand.Float64() >= table.DropChance {
+		return
+	}
+	if item := rollLoot(table.Items, nil); item != "" {
+		w.spawnPickup(item, x, y)
+	}
+}
+
+// Picks an item by weight, or "" from an empty table. Names are rolled in order so a seeded rng always gives the
+// same item. rng may be nil to use the shared source
+func rollLoot(items map[string]float64, rng *rand.Rand) string {
+	names := common.SortedKeys(items)
+	var total float64
+	for _, name := range names {
+		total += items[name]
+	}
+	r := rand.Float64
+	if rng != nil {
+		r = rng.Float64
+	}
+	roll := r() * total
+	for _, name := range names {
+		roll -= items[name]
+		if roll < 0 {
+			return name
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return names[len(names)-1]
+}
+
+// Applies the pickup's effect
+func (p *Player) collect(pk *Pickup) {
+	timeNow := time.Now().UnixMilli()
+	switch pk.data.Effect {
+	case common.EffectHeal:
+		p.health += pk.data.Amount
+		if p.health > PLAYERMAXHEALTH {
+			p.health = PLAYERMAXHEALTH
+		}
+	case common.EffectAmmo:
+		p.rockets += int(pk.data.Amount)
+	case common.EffectCoins:
+		p.coins += int(pk.data.Amount)
+	case common.EffectDamage:
+		p.damageMultiplier = pk.data.Amount
+		p.damageBoostUntil = timeNow + pk.data.DurationMs
+	case common.EffectFireRate:
+		p.fireRateMultiplier = pk.data.Amount
+		p.rapidFireUntil = timeNow + pk.data.DurationMs
+	case common.EffectShield:
+		if until := timeNow + pk.data.DurationMs; until > p.invulnerableUntil {
+			p.invulnerableUntil = until
+		}
+	}
+}
+
+// Gives players the pickups they touched this tick
+type PickupSystem struct{}
+
+func (s *PickupSystem) Update(w *World) {
+	w.pickups.Each(func(id EntityID, pk *Pickup) {
+		if pk.shouldRemove {
+			return
+		}
+		for _, e := range pk.collidingEntities {
+			p, ok := w.inputs.Get(e.id)
+			if !ok || p.shouldRemove || p.isDowned {
+				continue
+			}
+			p.collect(pk)
+			w.events.Publish(PickupCollected{p, pk.name, pk.data})
+			w.Despawn(&pk.Entity)
+			return
+		}
+	})
+}
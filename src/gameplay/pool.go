@@ -0,0 +1,29 @@
+package gameplay
+
+// Keeps finished objects to hand out again, so rapid fire and debris do not churn the GC.
+// Whatever Get returns still holds its old values and has to be reset by the caller
+type Pool[T any] struct {
+	free  []T
+	alloc func() T
+}
+
+func NewPool[T any](alloc func() T) *Pool[T] {
+	return &Pool[T]{alloc: alloc}
+}
+
+func (p *Pool[T]) Get() T {
+	n := len(p.free)
+	if n == 0 {
+		return p.alloc()
+	}
+	t := p.free[n-1]
+	var zero T
+	p.free[n-1] = zero
+	p.free = p.free[:n-1]
+	return t
+}
+
+// Hands t back. Nothing else may hold on to it
+func (p *Pool[T]) Put(t T) {
+	p.free = append(p.free, t)
+}
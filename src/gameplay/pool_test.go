@@ -0,0 +1,82 @@
+package gameplay
+
+import "testing"
+
+const (
+	// Ticks between each shooter's shots, 100ms at ebiten's 60 ticks per second
+	firefightFireTicks = 6
+	firefightZombies   = 20
+)
+
+// Four shooters on the left and a wall of zombies on the right, with nothing but air between them. Unpooled
+// never hands projectiles back, so every shot allocates a new one
+func newFirefight(pooled bool) (*World, []*Entity, []System) {
+	w := newTestWorld()
+	w.projectiles = NewStore[*Projectile](&w.registry)
+	w.projectilePool = NewPool(newPooledProjectile)
+	if pooled {
+		w.OnDespawn(func(e *Entity) {
+			if p, ok := w.projectiles.Get(e.id); ok {
+				w.projectilePool.Put(p)
+			}
+		})
+	}
+	shooters := make([]*Entity, 4)
+	for i := range shooters {
+		shooters[i] = NewEntity(0, float32(i)*2*TILEWIDTH, TILEWIDTH, 2*TILEWIDTH, 0, nil, nil, false)
+	}
+	for i := 0; i < firefightZombies; i++ {
+		z := NewEntity(20*TILEWIDTH, float32(i)*TILEWIDTH/2, TILEWIDTH, 2*TILEWIDTH, 0, nil, nil, false)
+		z.layer, z.mask = ZombieLayer, ProjectileLayer
+		z.health = 1
+		w.AddEntity(z)
+	}
+	w.applyLifecycle()
+	systems := []System{&ProjectileSystem{}, &PhysicsSystem{}, &CollisionSystem{}}
+	return w, shooters, systems
+}
+
+// Puts the zombies back where they started, so the fight never runs out of targets
+func healZombies(w *World) {
+	w.healths.Each(func(id EntityID, h *Health) {
+		if e, ok := w.entities.Get(id); ok && e.layer == ZombieLayer {
+			e.x, e.vx, e.vy = 20*TILEWIDTH, 0, 0
+			e.invulnerableUntil = 0
+			h.health = 1e9
+		}
+	})
+}
+
+func BenchmarkFirefight(b *testing.B) {
+	for _, pooled := range []bool{false, true} {
+		name := "unpooled"
+		if pooled {
+			name = "pooled"
+		}
+		b.Run(name, func(b *testing.B) {
+			w, shooters, systems := newFirefight(pooled)
+			shots, hits := 0, 0
+			Subscribe(&w.events, func(ev EntityDamaged) {
+				hits++
+			})
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if i%firefightFireTicks == 0 {
+					for _, s := range shooters {
+						p := NewProjectile(s.x+s.width, s.y+s.height/3, 18, 4, 30, 0, 25, w, nil)
+						p.owner = s
+						w.AddProjectile(p)
+						shots++
+					}
+				}
+				healZombies(w)
+				runTick(w, systems...)
+			}
+			b.StopTimer()
+			if flying := len(w.projectiles.items); hits+flying != shots {
+				b.Fatalf("%d shots, %d hits and %d still flying, the rest missed", shots, hits, flying)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package gameplay
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Jack-Craig/gogame/src/common"
+)
+
+const (
+	PREFABDIR = "res/world/chunks"
+)
+
+// Hand made chunk of level, see common.PrefabJson for the format
+type Prefab struct {
+	common.PrefabJson
+	name  string
+	width uint32
+}
+
+// Loads every chunk referenced by the biomes
+func LoadPrefabs(biomeData common.BiomeDataJson) map[string]*Prefab {
+	prefabs := make(map[string]*Prefab)
+	for _, b := range biomeData.Biomes {
+		for name := range b.Prefabs {
+			if _, ok := prefabs[name]; ok {
+				continue
+			}
+			p := &Prefab{name: name}
+			if err := common.LoadJSON(PrefabPath(name), &p.PrefabJson); err != nil {
+				log.Fatal(err)
+			}
+			p.width = uint32(len(p.Tiles[0]))
+			prefabs[name] = p
+		}
+	}
+	return prefabs
+}
+
+func PrefabPath(name string) string {
+	return fmt.Sprintf("%s/%s.json", PREFABDIR, name)
+}
+
+// Rolls for a chunk to follow biome b, returns nil for none
+func (l *Level) rollPrefab(b *Biome) *Prefab {
+	for _, name := range common.SortedKeys(b.Prefabs) {
+		if l.rng.Float64() < b.Prefabs[name] {
+			return l.prefabs[name]
+		}
+	}
+	return nil
+}
+
+// Starts placing p at the generation edge, with its left floor on the current ground height
+func (l *Level) startPrefab(p *Prefab) {
+	l.prefab = p
+	l.prefabStartX = l.worldXGen
+	l.prefabOffsetY = int(l.lastGroundY) - p.LeftFloor
+}
+
+// Fills col from column cx of the chunk, with the chunk's top row at offsetY
+func (p *Prefab) fillColumn(cx uint32, offsetY int, col *GenColumn) {
+	col.authored = true
+	foundGround := false
+	for y := uint32(0); y < LEVELHEIGHT; y++ {
+		row := int(y) - offsetY
+		kind := AirTile
+		if row >= len(p.Tiles) {
+			kind = SubsurfaceTile
+		} else if row >= 0 {
+			switch p.Tiles[row][cx] {
+			case common.PrefabSubsurface:
+				kind = SubsurfaceTile
+			case common.PrefabSurface:
+				kind = SurfaceTile
+			case common.PrefabClimbable:
+				kind = ClimbableTile
+			case common.PrefabZombie:
+				col.spawns = append(col.spawns, SpawnEvent{ZombieSpawn, float32(col.x) * TILEWIDTH, float32(y) * TILEWIDTH, "", nil})
+			case common.PrefabPickup:
+				col.spawns = append(col.spawns, SpawnEvent{PickupSpawn, float32(col.x) * TILEWIDTH, float32(y) * TILEWIDTH, "", nil})
+			}
+		}
+		col.tiles[y] = kind
+		if !foundGround && col.isSolid(y) {
+			col.groundY = y
+			foundGround = true
+		}
+	}
+}
+
+// Ground height in world rows after the chunk's right edge
+func (p *Prefab) rightFloorY(offsetY int) uint32 {
+	y := offsetY + p.RightFloor
+	if y < 1 {
+		return 1
+	} else if y >= int(LEVELHEIGHT) {
+		return LEVELHEIGHT - 1
+	}
+	return uint32(y)
+}
@@ -0,0 +1,131 @@
+package gameplay
+
+import "math"
+
+// First thing a ray touched
+type RayHit struct {
+	// In world coordinates
+	x, y float32
+	// How far along the ray, from 0 at the start to 1 at the end
+	t float32
+	// One of these is set
+	tile   *Tile
+	entity *Entity
+}
+
+// Casts a segment from x0, y0 to x1, y1 against solid tiles and entities on a layer in mask, returning the nearest
+// hit. Entities filter returns false for are passed through, filter may be nil. Use mask 0 to only test the world
+func (w *World) Raycast(x0, y0, x1, y1 float32, mask CollisionLayer, filter func(e *Entity) bool) (RayHit, bool) {
+	hit, isHit := w.raycastTiles(x0, y0, x1, y1)
+	if mask == 0 {
+		return hit, isHit
+	}
+	dx, dy := x1-x0, y1-y0
+	w.raycastIndex().Overlapping(min(x0, x1), max(x0, x1), func(e *Entity) {
+		if e.layer&mask == 0 || e.shouldRemove || (filter != nil && !filter(e)) {
+			return
+		}
+		minX, minY, maxX, maxY := e.bounds()
+		t, ok := rayBox(x0, y0, dx, dy, minX, minY, maxX, maxY)
+		if !ok || (isHit && t >= hit.t) {
+			return
+		}
+		hit = RayHit{x0 + dx*t, y0 + dy*t, t, nil, e}
+		isHit = true
+	})
+	return hit, isHit
+}
+
+// Colliders sorted on x so a ray only tests those it passes over. Built the first time it is needed each tick,
+// which for projectiles is before anything has moved
+func (w *World) raycastIndex() *Broadphase {
+	if w.rayIndexTick == w.tick {
+		return &w.rayIndex
+	}
+	w.rayIndexTick = w.tick
+	w.rayEntities = w.rayEntities[:0]
+	w.colliders.Each(func(id EntityID, c *Collider) {
+		if e, ok := w.liveEntity(id); ok {
+			w.rayEntities = append(w.rayEntities, e)
+		}
+	})
+	w.rayIndex.Sort(w.rayEntities)
+	return &w.rayIndex
+}
+
+// True if no solid tile lies between the two points
+func (w *World) HasLineOfSight(x0, y0, x1, y1 float32) bool {
+	_, isHit := w.raycastTiles(x0, y0, x1, y1)
+	return !isHit
+}
+
+// Walks the tile grid cell by cell along the segment, stopping where it first meets the solid part of a tile
+func (w *World) raycastTiles(x0, y0, x1, y1 float32) (RayHit, bool) {
+	dx, dy := x1-x0, y1-y0
+	gx, gy := toGrid(x0, y0)
+	endX, endY := toGrid(x1, y1)
+	// t at which the ray crosses the next grid line on each axis, and between grid lines
+	stepX, tMaxX, tDeltaX := gridStep(x0, dx, gx)
+	stepY, tMaxY, tDeltaY := gridStep(y0, dy, gy)
+	var t float32
+	for {
+		if tile := w.tileAtGrid(gx, gy); tile != nil {
+			if hitT, ok := tile.rayEntry(x0, y0, dx, dy, t, min(tMaxX, tMaxY, 1)); ok {
+				return RayHit{x0 + dx*hitT, y0 + dy*hitT, hitT, tile, nil}, true
+			}
+		}
+		if gx == endX && gy == endY {
+			return RayHit{}, false
+		}
+		if tMaxX < tMaxY {
+			t = tMaxX
+			tMaxX += tDeltaX
+			gx += stepX
+		} else {
+			t = tMaxY
+			tMaxY += tDeltaY
+			gy += stepY
+		}
+		if t > 1 {
+			return RayHit{}, false
+		}
+	}
+}
+
+func gridStep(origin, d float32, cell int32) (int32, float32, float32) {
+	inf := float32(math.Inf(1))
+	if d > 0 {
+		return 1, (float32(cell+1)*TILEWIDTH - origin) / d, TILEWIDTH / d
+	} else if d < 0 {
+		return -1, (float32(cell)*TILEWIDTH - origin) / d, -TILEWIDTH / d
+	}
+	return 0, inf, inf
+}
+
+// Slab test of a segment from x0, y0 along dx, dy against a box. Returns how far along the segment it enters
+func rayBox(x0, y0, dx, dy, minX, minY, maxX, maxY float32) (float32, bool) {
+	tMin, tMax := float32(0), float32(1)
+	for _, axis := range [2][4]float32{{x0, dx, minX, maxX}, {y0, dy, minY, maxY}} {
+		o, d, lo, hi := axis[0], axis[1], axis[2], axis[3]
+		if d == 0 {
+			if o < lo || o > hi {
+				return 0, false
+			}
+			continue
+		}
+		t1, t2 := (lo-o)/d, (hi-o)/d
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		if t1 > tMin {
+			tMin = t1
+		}
+		if t2 < tMax {
+			tMax = t2
+		}
+		if tMin > tMax {
+			return 0, false
+		}
+	}
+	return tMin, true
+}
@@ -0,0 +1,28 @@
+package gameplay
+
+import "testing"
+
+func TestProjectileRaycastsPastUnhurtable(t *testing.T) {
+	w := newTestWorld()
+	w.projectilePool = NewPool(newPooledProjectile)
+	shooter := NewEntity(0, 0, TILEWIDTH, TILEWIDTH, 0, nil, nil, false)
+	// Right in the way, but bullets cannot hurt it
+	shield := NewEntity(2*TILEWIDTH, 0, 4, TILEWIDTH, 0, nil, nil, false)
+	shield.layer, shield.immuneToGuns = ZombieLayer, true
+	zombie := NewEntity(2*TILEWIDTH+8, 0, 4, TILEWIDTH, 0, nil, nil, false)
+	zombie.layer, zombie.health = ZombieLayer, 100
+	w.AddEntity(shield)
+	w.AddEntity(zombie)
+	w.applyLifecycle()
+
+	p := NewProjectile(2*TILEWIDTH-8, TILEWIDTH/2, 2, 2, 30, 0, 25, w, nil)
+	p.owner = shooter
+	p.w = w
+	p.Update()
+	if zombie.health != 75 {
+		t.Errorf("zombie behind a gun immune entity has %v health, expected the bullet to reach it", zombie.health)
+	}
+	if !p.shouldRemove {
+		t.Errorf("bullet still flying, expected it to stop at the zombie")
+	}
+}
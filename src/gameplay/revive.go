@@ -0,0 +1,96 @@
+package gameplay
+
+import (
+	"math"
+	"time"
+
+	"github.com/Jack-Craig/gogame/src/input"
+)
+
+// Downed players bleed out unless a teammate stands next to them holding revive for long enough. Bleeding out is
+// paused while someone is reviving. Once nobody on a team is left standing everyone downed on it dies
+type ReviveSystem struct{}
+
+func (s *ReviveSystem) Update(w *World) {
+	timeNow := time.Now().UnixMilli()
+	var standing [NUMTEAMS]int
+	w.inputs.Each(func(id EntityID, p *Player) {
+		if !p.shouldRemove && !p.isDowned {
+			standing[w.teamOf(p)]++
+		}
+	})
+	w.inputs.Each(func(id EntityID, p *Player) {
+		if p.shouldRemove || !p.isDowned {
+			return
+		}
+		if reviver := w.reviverFor(p); reviver != p.revivedBy {
+			// Started, stopped or swapped, progress starts over
+			if p.revivedBy == nil {
+				p.bleedOutLeft = p.bleedOutAt - timeNow
+			}
+			p.revivedBy = reviver
+			p.reviveStart = timeNow
+		}
+		switch {
+		case standing[w.teamOf(p)] == 0:
+			p.Kill(p.downedBy)
+		case p.revivedBy == nil && timeNow >= p.bleedOutAt:
+			p.Kill(p.downedBy)
+		case p.revivedBy == nil:
+		case timeNow >= p.reviveStart+REVIVEMS:
+			p.revive(p.revivedBy, timeNow)
+		default:
+			p.bleedOutAt = timeNow + p.bleedOutLeft
+		}
+	})
+}
+
+// The player reviving p, or nil. Whoever is already reviving keeps going while they still can
+func (w *World) reviverFor(p *Player) *Player {
+	if p.revivedBy != nil && p.canRevive(p.revivedBy) {
+		return p.revivedBy
+	}
+	var reviver *Player
+	w.inputs.Each(func(id EntityID, r *Player) {
+		if reviver == nil && p.canRevive(r) {
+			reviver = r
+		}
+	})
+	return reviver
+}
+
+// Standing teammate, close enough and holding revive
+func (p *Player) canRevive(r *Player) bool {
+	if r == p || !p.w.sameTeam(p, r) || r.shouldRemove || r.isDowned || !r.pi.IsButtonPressed(input.JoyConY) {
+		return false
+	}
+	px, py := p.Center()
+	rx, ry := r.Center()
+	return math.Hypot(float64(rx-px), float64(ry-py)) <= float64(REVIVEDISTANCE)
+}
+
+// Gets p back up with some health and a moment of invulnerability
+func (p *Player) revive(reviver *Player, timeNow int64) {
+	p.isDowned = false
+	p.downedBy = nil
+	p.revivedBy = nil
+	p.health = REVIVEHEALTH
+	p.invulnerableUntil = timeNow + PLAYERIFRAMESMS
+	p.w.events.Publish(PlayerRevived{p, reviver})
+}
+
+// How far through being revived p is, from 0 to 1
+func (p *Player) reviveProgress(timeNow int64) float32 {
+	if !p.isDowned || p.revivedBy == nil {
+		return 0
+	}
+	return min(float32(timeNow-p.reviveStart)/float32(REVIVEMS), 1)
+}
+
+// How much of p's bleed out time is left, from 1 to 0
+func (p *Player) bleedOutFraction(timeNow int64) float32 {
+	if !p.isDowned || p.bleedOutMs == 0 {
+		return 0
+	}
+	return max(float32(p.bleedOutAt-timeNow)/float32(p.bleedOutMs), 0)
+}
@@ -0,0 +1,149 @@
+package gameplay
+
+import (
+	"github.com/Jack-Craig/gogame/src/graphics"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// The solid part of a tile. Slopes let entities walk up one tile rises without jumping
+type TileShape uint8
+
+const (
+	FullShape TileShape = iota
+	// Rises to the right over one tile
+	Slope45Up
+	Slope45Down
+	// Lower and upper halves of a rise to the right over two tiles
+	Slope22UpLow
+	Slope22UpHigh
+	// Upper and lower halves of a fall to the right over two tiles
+	Slope22DownHigh
+	Slope22DownLow
+)
+
+// Top of the solid part at the tile's left and right edges, as a fraction of the tile down from its top
+var shapeSurface = [...][2]float32{
+	FullShape:       {0, 0},
+	Slope45Up:       {1, 0},
+	Slope45Down:     {0, 1},
+	Slope22UpLow:    {1, .5},
+	Slope22UpHigh:   {.5, 0},
+	Slope22DownHigh: {0, .5},
+	Slope22DownLow:  {.5, 1},
+}
+
+// Names for the "shape" tile property in Tiled tilesets
+var TileShapeNames = map[string]TileShape{
+	"full":            FullShape,
+	"slope45up":       Slope45Up,
+	"slope45down":     Slope45Down,
+	"slope22uplow":    Slope22UpLow,
+	"slope22uphigh":   Slope22UpHigh,
+	"slope22downhigh": Slope22DownHigh,
+	"slope22downlow":  Slope22DownLow,
+}
+
+// Offset of the solid surface from the top of the tile at lx pixels from its left edge
+func (t *Tile) surfaceAt(lx float32) float32 {
+	s := shapeSurface[t.shape]
+	return (s[0] + (s[1]-s[0])*lx/TILEWIDTH) * TILEWIDTH
+}
+
+// True if the point lx, ly pixels into the tile is solid
+func (t *Tile) isSolidAt(lx, ly float32) bool {
+	if t.isPassable {
+		return false
+	}
+	return t.shape == FullShape || ly >= t.surfaceAt(lx)
+}
+
+// Where a ray from x0, y0 along dx, dy first meets the solid part of the tile, given it crosses the tile between
+// tEnter and tExit. The surface is a straight line, so the ray's depth below it changes linearly
+func (t *Tile) rayEntry(x0, y0, dx, dy, tEnter, tExit float32) (float32, bool) {
+	if t.isPassable {
+		return 0, false
+	}
+	if t.shape == FullShape {
+		return tEnter, true
+	}
+	depth := func(rt float32) float32 {
+		return y0 + dy*rt - t.y - t.surfaceAt(x0+dx*rt-t.x)
+	}
+	d0, d1 := depth(tEnter), depth(tExit)
+	if d0 >= 0 {
+		return tEnter, true
+	} else if d1 < 0 {
+		return 0, false
+	}
+	return tEnter + (tExit-tEnter)*d0/(d0-d1), true
+}
+
+func (t *Tile) Draw(screen *ebiten.Image) {
+	if t.shape == FullShape || t.im == nil {
+		t.GameObject.Draw(screen)
+		return
+	}
+	// Only the solid part of the sprite, as two triangles
+	camOffX, camOffY := t.w.camera.GetRenderOffset()
+	x, y := t.x+camOffX, t.y+camOffY
+	b := t.im.Bounds()
+	srcX, srcY, srcW, srcH := float32(b.Min.X), float32(b.Min.Y), float32(b.Dx()), float32(b.Dy())
+	s := shapeSurface[t.shape]
+	corners := [4][2]float32{{0, s[0]}, {1, s[1]}, {1, 1}, {0, 1}}
+	vertices := make([]ebiten.Vertex, 4)
+	for i, c := range corners {
+		vertices[i] = ebiten.Vertex{
+			DstX: x + c[0]*TILEWIDTH, DstY: y + c[1]*TILEWIDTH,
+			SrcX: srcX + c[0]*srcW, SrcY: srcY + c[1]*srcH,
+			ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1,
+		}
+	}
+	screen.DrawTriangles(vertices, []uint16{0, 1, 2, 0, 2, 3}, t.im, nil)
+}
+
+// Before col is added, fills the open corner of one tile rises and falls between it and the columns before it with
+// slopes, using two tile slopes where the ground either side is flat. Hand made chunks are left as designed
+func (l *Level) placeSlopes(b *Biome, col *Column, groundY uint32, authored bool) {
+	if authored || l.lastAuthored || len(l.pending) == 0 || col.x == 0 {
+		return
+	}
+	slope := func(shape TileShape) TileSpec {
+		return TileSpec{sprite: graphics.SpriteID(b.SurfaceTile), hasSprite: true, health: b.TileHealth, shape: shape, material: b.Material}
+	}
+	prev := &l.pending[len(l.pending)-1]
+	switch {
+	case groundY+1 == l.lastGroundY:
+		// Rise, slope up from the previous column
+		y := groundY
+		if !isOpenAbove(prev, y) {
+			return
+		}
+		if len(l.pending) > 1 && l.prevGroundY == l.lastGroundY && isOpenAbove(&l.pending[len(l.pending)-2], y) {
+			l.pending[len(l.pending)-2].tiles[y] = slope(Slope22UpLow)
+			prev.tiles[y] = slope(Slope22UpHigh)
+		} else {
+			prev.tiles[y] = slope(Slope45Up)
+		}
+	case groundY == l.lastGroundY+1:
+		// Fall, slope down in this column
+		if y := l.lastGroundY; isOpenAbove(col, y) {
+			col.tiles[y] = slope(Slope45Down)
+		}
+	case groundY == l.lastGroundY && groundY > 0:
+		// Flat after a fall, spread the slope over both columns
+		y := groundY - 1
+		if prev.tiles[y].shape == Slope45Down && isOpenAbove(col, y) {
+			prev.tiles[y] = slope(Slope22DownHigh)
+			col.tiles[y] = slope(Slope22DownLow)
+		}
+	}
+}
+
+// True if tile y of col is open air sitting on a full solid tile
+func isOpenAbove(col *Column, y uint32) bool {
+	if y+1 >= uint32(len(col.tiles)) {
+		return false
+	}
+	below := col.tiles[y+1]
+	return col.tiles[y] == airSpec && !below.isPassable && below.shape == FullShape
+}
@@ -0,0 +1,135 @@
+package gameplay
+
+func DefaultSystems() []System {
+	return []System{
+		&InputSystem{},
+		&ReviveSystem{},
+		&AISystem{},
+		&ProjectileSystem{},
+		&ZombieWallSystem{},
+		&PhysicsSystem{},
+		&CollisionSystem{},
+		&PickupSystem{},
+		&HealthSystem{},
+	}
+}
+
+// Versus rounds have no zombies, no zombie wall and nobody is downed
+func ArenaSystems() []System {
+	return []System{
+		&InputSystem{},
+		&ProjectileSystem{},
+		&PhysicsSystem{},
+		&CollisionSystem{},
+		&PickupSystem{},
+		&HealthSystem{},
+		&ArenaSystem{},
+	}
+}
+
+// Returns the entity with id, unless it is leaving the world this tick
+func (w *World) liveEntity(id EntityID) (*Entity, bool) {
+	e, ok := w.entities.Get(id)
+	return e, ok && !e.shouldRemove
+}
+
+// Players act on their controller input
+type InputSystem struct{}
+
+func (s *InputSystem) Update(w *World) {
+	w.inputs.Each(func(id EntityID, p *Player) {
+		if !p.shouldRemove {
+			p.Update()
+		}
+	})
+}
+
+type AISystem struct{}
+
+func (s *AISystem) Update(w *World) {
+	w.ais.Each(func(id EntityID, ai ZombieAI) {
+		if _, ok := w.liveEntity(id); ok {
+			ai.Update()
+		}
+	})
+}
+
+// Projectiles hit whatever they touched last tick, or are about to pass through
+type ProjectileSystem struct{}
+
+func (s *ProjectileSystem) Update(w *World) {
+	w.projectiles.Each(func(id EntityID, p *Projectile) {
+		if !p.shouldRemove {
+			p.Update()
+		}
+	})
+}
+
+// Kills anything the zombie wall catches up with, then moves it along
+type ZombieWallSystem struct{}
+
+func (s *ZombieWallSystem) Update(w *World) {
+	w.healths.Each(func(id EntityID, h *Health) {
+		e, ok := w.liveEntity(id)
+		if !ok {
+			return
+		}
+		furthestRight := float64(e.x + e.width)
+		if furthestRight <= w.zombieWallX-float64(TILEWIDTH*float32(w.level.Height())-e.y)*zombieWallM {
+			e.Kill(nil)
+		}
+	})
+	w.zombieWallX += (.05 * float64(TILEWIDTH))
+}
+
+// Applies gravity and moves entities through the world
+type PhysicsSystem struct{}
+
+func (s *PhysicsSystem) Update(w *World) {
+	w.velocities.Each(func(id EntityID, v *Velocity) {
+		e, ok := w.liveEntity(id)
+		if !ok || w.nearUnloadedChunk(e) {
+			// Frozen until the ground under it is loaded again, rather than falling through it
+			return
+		}
+		if !v.isClimbing {
+			e.AddVel(0, w.gravity*v.gravityMultiplier)
+		}
+		e.Update()
+	})
+}
+
+// Finds every overlapping pair of colliders, recording them and pushing solid ones apart
+type CollisionSystem struct {
+	broadphase Broadphase
+	colliding  []*Entity
+}
+
+func (s *CollisionSystem) Update(w *World) {
+	s.colliding = s.colliding[:0]
+	w.colliders.Each(func(id EntityID, c *Collider) {
+		c.collidingEntities = c.collidingEntities[:0]
+		if e, ok := w.liveEntity(id); ok {
+			s.colliding = append(s.colliding, e)
+		}
+	})
+	s.broadphase.Pairs(s.colliding, func(ei, ej *Entity) {
+		if !canCollide(ei, ej) {
+			return
+		}
+		if m, ok := collide(ei, ej); ok {
+			m.resolve()
+		}
+	})
+}
+
+// Despawns anything that has run out of health
+type HealthSystem struct{}
+
+func (s *HealthSystem) Update(w *World) {
+	w.healths.Each(func(id EntityID, h *Health) {
+		if e, ok := w.liveEntity(id); ok && h.health <= 0 && !h.isDowned {
+			w.Despawn(e)
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package gameplay
+
+import "image/color"
+
+// What kind of match the menu starts
+type GameMode int
+
+const (
+	// Everyone on one side against the zombies
+	CoopMode GameMode = iota
+	// Two teams race across the same level and can shoot each other
+	RaceMode
+	// Two teams fight on a single screen, respawning until the round ends
+	ArenaMode
+	numGameModes
+)
+
+const NUMTEAMS = 2
+
+var teamColors = [NUMTEAMS]color.Color{
+	color.RGBA{0xe0, 0x50, 0x50, 0xff},
+	color.RGBA{0x50, 0x80, 0xe0, 0xff},
+}
+
+func (m GameMode) String() string {
+	switch m {
+	case RaceMode:
+		return "Race"
+	case ArenaMode:
+		return "Arena"
+	default:
+		return "Co-op"
+	}
+}
+
+// The side p plays for. Everyone is on one side in co-op
+func (w *World) teamOf(p *Player) int {
+	if w.mode == CoopMode {
+		return 0
+	}
+	return p.team
+}
+
+func (w *World) sameTeam(a, b *Player) bool {
+	return w.teamOf(a) == w.teamOf(b)
+}
+
+// True if any player could be hit by a gun this match
+func (w *World) playersCanBeShot() bool {
+	return w.friendlyFire || w.mode != CoopMode
+}
+
+// Whether source's bullets and explosions hurt target. Players always hurt the other team, and only hurt their own
+// team, themselves included, with friendly fire on
+func (w *World) gunsCanHurt(source, target *Entity) bool {
+	if target.Collider != nil && target.immuneToGuns {
+		return false
+	}
+	victim, shooter := w.playerFor(target), w.playerFor(source)
+	if victim == nil || shooter == nil {
+		return true
+	}
+	return w.friendlyFire || !w.sameTeam(shooter, victim)
+}
+
+// Whether killing target counts towards source's kills. Teammates and yourself do not
+func (w *World) creditsKill(source, target *Entity) bool {
+	victim, shooter := w.playerFor(target), w.playerFor(source)
+	return shooter != nil && (victim == nil || !w.sameTeam(shooter, victim))
+}
+
+// The first player to leave the end of the level wins the race for their team
+func (w *World) subscribeRace() {
+	w.winningTeam = -1
+	Subscribe(&w.events, func(ev PlayerFinished) {
+		if w.mode == RaceMode && w.winningTeam < 0 {
+			w.winningTeam = w.teamOf(ev.player)
+			w.events.Publish(RaceWon{w.winningTeam})
+		}
+	})
+}
@@ -1,16 +1,14 @@
 package gameplay
 
 import (
-	"fmt"
 	"image/color"
-	"math/rand"
+	"log"
+	"math"
 
 	"github.com/Jack-Craig/gogame/src/common"
 	"github.com/Jack-Craig/gogame/src/graphics"
-	"github.com/aquilax/go-perlin"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"github.com/hajimehoshi/ebiten/v2/text"
 )
 
 const (
@@ -18,271 +16,409 @@ const (
 	TILEWIDTH float32 = 32
 	// Maximum velocity of entities
 	MAXVEL float32 = 12
-	// Total length of world tile 2d array
-	WORLDBUFFERLEN uint32 = 120
-	// Total height of world tile 2d array
-	WORLDBUFFERHEIGHT uint32 = 30
-	// Max length of buffer to generate (cap)
+	// Height of procedurally generated levels, in tiles
+	LEVELHEIGHT uint32 = 30
+	// Width and height of a tile chunk, in tiles
+	CHUNKSIZE int32 = 16
+	// Chunks within this many of the screen are loaded
+	CHUNKLOADMARGIN int32 = 1
+	// Chunks further than this many from the screen are unloaded, more than the load margin so chunks do not flicker at the edge
+	CHUNKUNLOADMARGIN int32 = 3
+	// Max columns to generate ahead of the camera (cap)
 	MAXWORLDGENBUFFERLEN uint32 = 80
-	// Min length of buffer to generate (trigger)
+	// Min columns generated ahead of the camera (trigger)
 	MINWORLDGENBUFFERLEN uint32 = 40
-	// Length of biome chunks
-	BIOMELENGTH       uint32  = 8
+	// Columns at the start of a biome that blend height from the previous one
+	BIOMEBLENDLENGTH uint32 = 6
+	// Columns at the start of a biome over which the sky and background fade in
+	SKYBLENDLENGTH    uint32  = 16
 	PLAYERWORLDSTARTX float32 = TILEWIDTH
-	PLAYERWORLDSTARTY float32 = TILEWIDTH * float32(WORLDBUFFERHEIGHT-20)
+	PLAYERWORLDSTARTY float32 = TILEWIDTH * float32(LEVELHEIGHT-20)
 	TOTALTILES        uint32  = 4
 	zombieWallM       float64 = .25
+	// Vertical speed of entities on ladders and vines
+	CLIMBSPEED float32 = 3
+	// Highest ledge, in pixels, that entities walk up or down without jumping or falling
+	MAXSTEPHEIGHT float32 = TILEWIDTH / 2
+	// Milliseconds an entity loses control for after being hit
+	HITSTUNMS int64 = 200
+	// Milliseconds players cannot be hurt for after being hit
+	PLAYERIFRAMESMS int64 = 1000
+	// Players start each level with, and can heal up to, this much health
+	PLAYERMAXHEALTH float32 = 100
+	// Rockets each player starts a level with, ammo pickups give more
+	PLAYERSTARTROCKETS int = 5
+	// Milliseconds a downed player has before they die, unless a teammate revives them
+	BLEEDOUTMS int64 = 15000
+	// Milliseconds a teammate has to hold revive next to a downed player
+	REVIVEMS int64 = 3000
+	// How close a teammate has to stand to revive, centre to centre
+	REVIVEDISTANCE float32 = TILEWIDTH * 3 / 2
+	// Revived players get back up with this much health
+	REVIVEHEALTH float32 = PLAYERMAXHEALTH / 2
+	// Downed players crawl at this fraction of their running speed
+	DOWNEDCRAWLMULTIPLIER float32 = .25
+	// Milliseconds players cannot be hurt for after respawning in the arena
+	ARENASPAWNPROTECTIONMS int64 = 2000
+	// Milliseconds the scoreboard shows before a rematch can be started
+	SCOREBOARDDELAYMS int64 = 2000
+	// Milliseconds entities flash after being hit, and how fast invulnerable players blink
+	HITFLASHMS int64 = 120
+	BLINKMS    int64 = 80
+	// Speed entities are knocked back at by bullets, zombie attacks and explosions
+	BULLETKNOCKBACK    float32 = 3
+	ZOMBIEKNOCKBACK    float32 = 6
+	EXPLOSIONKNOCKBACK float32 = 10
+	// Minimum height difference between columns, in tiles, that gets a ladder or vine
+	MINCLIMBABLECLIFF uint32 = 2
 )
 
 type World struct {
 	Handler
-	camera                                 *Camera
-	gameObjects                            []*GameObject
-	zombieObjects                          []*Zombie
-	entityObjects                          []*Entity
-	playerObjects                          []*Player
-	projectiles                            []*Projectile
-	gravity                                float32
-	worldTiles                             [WORLDBUFFERHEIGHT][WORLDBUFFERLEN]*Tile
+	camera *Camera
+	// Every entity in the world by component, and the systems run over them each tick
+	registry    Registry
+	entities    *Store[*Entity]
+	transforms  *Store[*Transform]
+	velocities  *Store[*Velocity]
+	colliders   *Store[*Collider]
+	healths     *Store[*Health]
+	sprites     *Store[*Sprite]
+	ais         *Store[ZombieAI]
+	inputs      *Store[*Player]
+	projectiles *Store[*Projectile]
+	pickups     *Store[*Pickup]
+	// Name of the loot table dropped from on death
+	loot *Store[string]
+	// Whatever spawned the zombie, told when it leaves
+	spawners *Store[Spawner]
+	systems  []System
+	// Counts calls to Update
+	tick int64
+	// Colliders sorted for Raycast, and the tick they were sorted on
+	rayIndex     Broadphase
+	rayIndexTick int64
+	rayEntities  []*Entity
+	// Entities joining and leaving at the end of the tick, and who to tell when they do
+	spawnQueue, spareSpawns     []pendingSpawn
+	despawnQueue, spareDespawns []*Entity
+	onSpawn, onDespawn          []func(e *Entity)
+	particles                   []*Particle
+	// Reused once they despawn or die
+	projectilePool *Pool[*Projectile]
+	particlePool   *Pool[*Particle]
+	gravity        float32
+	// Loaded chunks around the camera, and everything placed in every chunk so far
+	chunks                                 map[ChunkCoord]*TileChunk
+	chunkData                              map[ChunkCoord]*chunkData
 	inited, canLeave, allPlayersDoneOrDead bool
 	bg                                     *Background
-	level                                  *Level
-	zombieWallX                            float64
+	level                                  LevelSource
+	gen                                    *GenWorker
+	movement                               common.MovementJson
+	pickupData                             common.PickupDataJson
+	events                                 EventBus
+	hud                                    *HUD
+	// Versus round state, nil outside of ArenaMode
+	arena *Arena
+	// Biome the camera was last in
+	curBiome string
+	// Team that won the race, -1 until someone finishes
+	winningTeam int
+	zombieWallX float64
+	// In array coordinates, start and end of visible world. Does not wrap
+	worldXStart uint32
+	worldXEnd   uint32
+	// In array coordinates, the next column to take from the level. Does not wrap
+	worldXGen uint32
 }
 
 func NewWorld(handler Handler) *World {
 	w := &World{Handler: handler}
 	w.camera = NewCamera(w)
-	for _, player := range handler.players {
-		player.w = w
-		player.x = PLAYERWORLDSTARTX
-		player.y = PLAYERWORLDSTARTX
-		player.shouldRemove = false
-		player.health = 100
+	w.chunks = make(map[ChunkCoord]*TileChunk)
+	w.chunkData = make(map[ChunkCoord]*chunkData)
+	w.entities = NewStore[*Entity](&w.registry)
+	w.transforms = NewStore[*Transform](&w.registry)
+	w.velocities = NewStore[*Velocity](&w.registry)
+	w.colliders = NewStore[*Collider](&w.registry)
+	w.healths = NewStore[*Health](&w.registry)
+	w.sprites = NewStore[*Sprite](&w.registry)
+	w.ais = NewStore[ZombieAI](&w.registry)
+	w.inputs = NewStore[*Player](&w.registry)
+	w.projectiles = NewStore[*Projectile](&w.registry)
+	w.pickups = NewStore[*Pickup](&w.registry)
+	w.loot = NewStore[string](&w.registry)
+	w.spawners = NewStore[Spawner](&w.registry)
+	w.rayIndexTick = -1
+	w.projectilePool = NewPool(newPooledProjectile)
+	w.particlePool = NewPool(func() *Particle { return &Particle{} })
+	w.OnDespawn(func(e *Entity) {
+		if p, ok := w.projectiles.Get(e.id); ok {
+			w.projectilePool.Put(p)
+		}
+		if s, ok := w.spawners.Get(e.id); ok {
+			s.Despawned()
+		}
+	})
+	if err := common.LoadJSON("res/movement.json", &w.movement); err != nil {
+		log.Fatal(err)
+	}
+	if err := common.LoadJSON("res/pickups.json", &w.pickupData); err != nil {
+		log.Fatal(err)
+	}
+	w.generateLevel()
+	w.systems = DefaultSystems()
+	if w.arena != nil {
+		w.systems = ArenaSystems()
+	}
+	w.gen = NewGenWorker(w.level)
+	w.subscribeRace()
+	w.hud = NewHUD(w)
+	startX, startY := w.level.PlayerStart()
+	for i, player := range handler.players {
+		if w.arena != nil {
+			startX, startY = w.arena.startingSpawn(i)
+		}
+		w.resetPlayer(player, startX, startY)
 		player.walkAnimation = *w.gdl.GenerateAnimation(graphics.UserWalkFrame1, graphics.UserWalkFrame6)
 		player.idleAnimation = *w.gdl.GenerateAnimation(graphics.UserIdleFrame1, graphics.UserIdleFrame3)
-		w.gameObjects = append(w.gameObjects, &player.GameObject)
-		w.entityObjects = append(w.entityObjects, &player.Entity)
-		w.playerObjects = append(w.playerObjects, player)
+		w.AddPlayer(player)
+		w.events.Publish(PlayerJoined{player})
 	}
+	// Players feel it in their controller when hit
+	Subscribe(&w.events, func(ev EntityDamaged) {
+		if p := w.playerFor(ev.target); p != nil && p.pi != nil {
+			p.pi.SendRumble()
+		}
+	})
+	Subscribe(&w.events, func(ev EntityKilled) {
+		if p := w.playerFor(ev.target); p != nil {
+			p.isDead = true
+		}
+		// Only drop loot for kills, not the zombie wall
+		if table, ok := w.loot.Get(ev.target.id); ok && ev.source != nil {
+			w.dropLoot(table, ev.target.x, ev.target.y)
+		}
+	})
+	w.applyLifecycle()
 	w.bg = NewBackground(w)
 	w.gravity = .25
-	w.generateLevel()
 	w.inited = true
 	return w
 }
 
-func (w *World) generateLevel() {
-	w.level = NewLevel(w, 100)
-	w.level.initWorld()
+// Puts p back at x, y with full health and no power ups, ready to be added to the world
+func (w *World) resetPlayer(p *Player, x, y float32) {
+	p.x = x
+	p.y = y
+	p.vx, p.vy = 0, 0
+	p.isClimbing = false
+	p.health = PLAYERMAXHEALTH
+	p.isDowned = false
+	p.downedBy = nil
+	p.revivedBy = nil
+	// Nobody revives anyone in the arena
+	p.bleedOutMs = BLEEDOUTMS
+	if w.arena != nil {
+		p.bleedOutMs = 0
+	}
+	p.rockets = PLAYERSTARTROCKETS
+	p.damageBoostUntil = 0
+	p.rapidFireUntil = 0
+	p.isDead = false
 }
 
-func (w *World) Update() {
-	w.camera.Update()
-	w.level.Update()
-
-	w.allPlayersDoneOrDead = true
-	for _, player := range w.playerObjects {
-		if !w.camera.IsInsideCamera(player.x, -1) {
-			player.shouldRemove = true
-		}
-		if !player.shouldRemove {
-			// Remove from entities, gameobjects, keep in players
-			w.allPlayersDoneOrDead = false
+func (w *World) generateLevel() {
+	if w.mapPath != "" && w.mode != ArenaMode {
+		level, err := NewAuthoredLevel(w.mapPath, w.gdl, w.pickupData.Pickups)
+		if err != nil {
+			log.Fatal(err)
 		}
-		player.Update()
+		w.level = level
+		return
 	}
-	for i, gObj := range w.gameObjects {
-		if gObj.shouldRemove {
-			common.Remove(&w.gameObjects, i)
-			continue
-		}
+	var biomeData common.BiomeDataJson
+	if err := common.LoadJSON("res/world/biomes.json", &biomeData); err != nil {
+		log.Fatal(err)
 	}
-
-	for i, zombie := range w.zombieObjects {
-		zombie.Update()
-		if zombie.shouldRemove {
-			common.Remove(&w.zombieObjects, i)
-			continue
+	if w.mode == ArenaMode {
+		var arenaData common.ArenaJson
+		if err := common.LoadJSON(ARENAPATH, &arenaData); err != nil {
+			log.Fatal(err)
 		}
-	}
-
-	for i, projectile := range w.projectiles {
-		projectile.Update()
-		if projectile.shouldRemove {
-			common.Remove(&w.projectiles, i)
-			continue
+		biome, ok := biomeData.Biomes[arenaData.Biome]
+		if !ok {
+			log.Fatalf("%s: unknown biome %q", ARENAPATH, arenaData.Biome)
 		}
+		w.arena = NewArena(w, arenaData, biome)
+		w.level = w.arena.level
+		return
 	}
-	for i, entity := range w.entityObjects {
-		if entity.health <= 0 {
-			entity.shouldRemove = true
-		}
-		if entity.shouldRemove {
-			common.Remove(&w.entityObjects, i)
-			continue
-		}
+	w.level = NewLevel(100, w.seed, biomeData, LoadPrefabs(biomeData), w.pickupData.LootTables["terrain"])
+}
 
-		furthestRight := float64(entity.x + entity.width)
-		if furthestRight <= w.zombieWallX-float64(TILEWIDTH*float32(WORLDBUFFERHEIGHT)-entity.y)*zombieWallM {
-			entity.health = 0
-		}
+// Stops background generation, call when the world is finished with
+func (w *World) Close() {
+	w.gen.Stop()
+}
 
-		entity.AddVel(0, w.gravity*entity.gravityMultiplier)
-		entity.Update()
-		entity.collidingEntities = nil
+func (w *World) Update() {
+	w.tick++
+	w.camera.Update()
+	w.updateTiles()
+	if w.arena == nil {
+		w.updateRoster()
+	}
+	if biome := w.level.BiomeAt(w.camera.CenterX()); biome != w.curBiome {
+		w.curBiome = biome
+		w.events.Publish(BiomeEntered{biome})
 	}
 
-	for i := 0; i < len(w.entityObjects); i++ {
-		ei := w.entityObjects[i]
-		eiX, eiY, eiWidth, eiHeight := float64(ei.x), float64(ei.y), float64(ei.width), float64(ei.height)
-		for j := i + 1; j < len(w.entityObjects); j++ {
-			ej := w.entityObjects[j]
-			// Collision
-			isCollision := true
-			ejX, ejY, ejWidth, ejHeight := float64(ej.x), float64(ej.y), float64(ej.width), float64(ej.height)
-			for _, norm := range ei.normals {
-				mini, maxi := common.MinMaxProjection(eiX, eiY, eiWidth, eiHeight, norm)
-				minj, maxj := common.MinMaxProjection(ejX, ejY, ejWidth, ejHeight, norm)
-
-				if maxi < minj || maxj < mini {
-					isCollision = false
-					break
-				}
-			}
+	for _, system := range w.systems {
+		system.Update(w)
+	}
 
-			for _, norm := range ej.normals {
-				if !isCollision {
-					break
-				}
-				mini, maxi := common.MinMaxProjection(eiX, eiY, eiWidth, eiHeight, norm)
-				minj, maxj := common.MinMaxProjection(ejX, ejY, ejWidth, ejHeight, norm)
+	alive := w.particles[:0]
+	for _, particle := range w.particles {
+		particle.Update()
+		if !particle.shouldRemove {
+			alive = append(alive, particle)
+		} else {
+			w.particlePool.Put(particle)
+		}
+	}
+	w.particles = alive
+	// Before the lifecycle, so subscribers can still read the components of anything that died
+	w.events.Flush()
+	w.applyLifecycle()
+}
 
-				if maxi < minj || maxj < mini {
-					isCollision = false
-					break
-				}
+// Players leave the level off the edges of the screen, and it ends once all of them have left or died
+func (w *World) updateRoster() {
+	w.allPlayersDoneOrDead = true
+	for _, player := range w.players {
+		if !w.camera.IsInsideCamera(player.x, -1) {
+			if !player.shouldRemove && !player.isDead && player.x > w.camera.CenterX() {
+				w.events.Publish(PlayerFinished{player})
 			}
-			if isCollision {
-				ei.collidingEntities = append(ei.collidingEntities, ej)
-				ej.collidingEntities = append(ej.collidingEntities, ei)
+			w.Despawn(&player.Entity)
+		}
+		if !player.shouldRemove && !player.isDead {
+			w.allPlayersDoneOrDead = false
+		}
+	}
+	if w.allPlayersDoneOrDead {
+		var survivors []*Player
+		for _, player := range w.players {
+			if !player.isDead {
+				survivors = append(survivors, player)
 			}
-
 		}
+		w.events.Publish(LevelCompleted{survivors})
 	}
-	w.zombieWallX += (.05 * float64(TILEWIDTH))
-}
-func (w *World) AddEntity(e *Entity) {
-	e.w = w
-	w.gameObjects = append(w.gameObjects, &e.GameObject)
-	w.entityObjects = append(w.entityObjects, e)
 }
 
-func (w *World) AddProjectile(b *Projectile) {
-	w.AddEntity(&b.Entity)
-	w.projectiles = append(w.projectiles, b)
+// Returns the player whose entity is e, or nil if it is not a player
+func (w *World) playerFor(e *Entity) *Player {
+	for _, p := range w.players {
+		if &p.Entity == e {
+			return p
+		}
+	}
+	return nil
 }
 
 func (w *World) Draw(screen *ebiten.Image) {
 	if !w.inited {
 		return
 	}
-	screen.Fill(color.RGBA{135, 205, 235, 255})
 	screenBounds := screen.Bounds().Max
 	w.camera.screenWidth = float32(screenBounds.X)
 	w.camera.screenHeight = float32(screenBounds.Y)
+	screen.Fill(skyColor(w.level.BlendAt(w.camera.CenterX())))
 	w.bg.Draw(screen)
 
-	if w.level.toBufferIndex(w.level.worldXStart) > w.level.toBufferIndex(w.level.worldXEnd+1) {
-		for x := uint32(0); x < w.level.toBufferIndex(w.level.worldXStart); x++ {
-			for y := uint32(0); y < WORLDBUFFERHEIGHT; y++ {
-				w.worldTiles[y][x].Draw(screen)
-			}
-		}
-		for x := w.level.toBufferIndex(w.level.worldXStart); x < WORLDBUFFERLEN; x++ {
-			for y := uint32(0); y < WORLDBUFFERHEIGHT; y++ {
-				w.worldTiles[y][x].Draw(screen)
-			}
-		}
-	} else {
-		for x := w.level.toBufferIndex(w.level.worldXStart); x <= w.level.toBufferIndex(w.level.worldXEnd+1); x++ {
-			for y := uint32(0); y < WORLDBUFFERHEIGHT; y++ {
-				w.worldTiles[y][x].Draw(screen)
+	minX, minY, maxX, maxY := w.visibleGrid()
+	for gx := minX; gx <= maxX; gx++ {
+		for gy := minY; gy <= maxY; gy++ {
+			if tile := w.tileAtGrid(gx, gy); tile != nil {
+				tile.Draw(screen)
 			}
 		}
 	}
 
-	for _, gobj := range w.gameObjects {
-		gobj.Draw(screen)
-	}
-	for _, entity := range w.entityObjects {
-		entity.Draw(screen)
+	for _, particle := range w.particles {
+		particle.Draw(screen)
 	}
-	for x, playerObj := range w.playerObjects {
-		w.DrawPlayerInfo(x+1, playerObj, screen)
+	w.sprites.Each(func(id EntityID, s *Sprite) {
+		e, _ := w.entities.Get(id)
+		e.Draw(screen)
+	})
+	w.hud.Draw(screen)
+	if w.arena == nil {
+		y := float64(TILEWIDTH * float32(w.level.Height()))
+		x2 := zombieWallM * y
+		ebitenutil.DrawLine(screen, w.zombieWallX+float64(w.camera.offX), float64(w.camera.offY)+y, w.zombieWallX-x2+float64(w.camera.offX), 0, color.Black)
 	}
-	y := float64(TILEWIDTH * float32(WORLDBUFFERHEIGHT))
-	x2 := zombieWallM * y
-	ebitenutil.DrawLine(screen, w.zombieWallX+float64(w.camera.offX), float64(w.camera.offY)+y, w.zombieWallX-x2+float64(w.camera.offX), 0, color.Black)
 	w.camera.Draw(screen)
 }
 
 // Given an x and y in world coordinates, returns true if there is a tile there and false otherwise
 func (w *World) IsWorldCollision(x, y float32) bool {
-	gridX, gridY := w.worldToBuffer(x, y)
-	if int(gridY) >= len(w.worldTiles) {
-		return false
-	}
-	if int(gridX) >= len(w.worldTiles[0]) {
-		return false
-	}
-	tile := w.worldTiles[gridY][gridX]
-	return !tile.isPassable
+	tile := w.tileAt(x, y)
+	return tile != nil && tile.isSolidAt(x-tile.x, y-tile.y)
 }
 
-func (w *World) worldToBuffer(x, y float32) (uint32, uint32) {
-	bufferY := uint32(y / TILEWIDTH)
-	gridX := uint32(x / TILEWIDTH)
-	bufferX := gridX % WORLDBUFFERLEN
-	return bufferX, bufferY
+// Given an x and y in world coordinates, returns true if there is a climbable tile there
+func (w *World) IsClimbable(x, y float32) bool {
+	tile := w.tileAt(x, y)
+	return tile != nil && tile.isClimbable
 }
 
-// Should probably be moved to player object? But is a UI elem so idk
-func (w *World) DrawPlayerInfo(x int, player *Player, screen *ebiten.Image) {
-	renderY := int(w.camera.screenHeight)
-	renderX := int(w.camera.screenWidth * (float32(x) / float32(len(w.playerObjects)+1)))
-
-	// Render player info background thing
-	bo := w.gdl.GetSpriteImage(graphics.PlayerInfo)
-	width, height := bo.Size()
-	scale := int(TILEWIDTH*4) / width
-	realWidth := width * scale
-	realHeight := height * scale
-	boxOp := ebiten.DrawImageOptions{}
-	boxOp.GeoM.Scale(float64(scale), float64(scale))
-	boxOp.GeoM.Translate(float64(renderX-realWidth/2), float64(renderY-realHeight))
-	screen.DrawImage(bo, &boxOp)
+// Returns the tile at world coordinates x, y, or nil if its chunk is not loaded
+func (w *World) tileAt(x, y float32) *Tile {
+	return w.tileAtGrid(toGrid(x, y))
+}
 
-	// Render player name, health
-	statusText := fmt.Sprintf("%0.f", player.health)
-	boxSize := text.BoundString(*w.gdl.GetFontNormal(), statusText)
-	textWidth := boxSize.Size().X
-	textHeight := boxSize.Size().Y
-	f := w.gdl.GetFontNormal()
-	text.Draw(screen, player.name, *w.gdl.GetFontSmall(), renderX-textWidth/2, renderY-textHeight/2-24, color.White)
-	text.Draw(screen, statusText, *f, renderX-textWidth/2, renderY-textHeight/2, color.White)
+// Damages the tile at world coordinates x, y. Returns true if it broke
+func (w *World) DamageTileAt(x, y, damage float32) bool {
+	tile := w.tileAt(x, y)
+	if tile == nil {
+		return false
+	}
+	return tile.Damage(damage)
+}
 
-	// Render tiny player (or skull)
-	op := ebiten.DrawImageOptions{}
-	guyScale := 1.2 * float64(height) / float64(graphics.TILESIZE)
-	guySize := guyScale * float64(graphics.TILESIZE)
-	op.GeoM.Scale(guyScale, guyScale)
-	op.GeoM.Translate(float64(renderX)-guySize-float64(textWidth)/2-10, float64(renderY-textHeight)-guySize/2)
-	if player.isDead {
-		screen.DrawImage(w.gdl.GetSpriteImage(graphics.Skull), &op)
-	} else {
-		screen.DrawImage(player.im, &op)
+// Damages tiles and entities within radius of x, y, falling off linearly from the center. source is credited
+// with the damage, and may be nil
+func (w *World) Explode(source *Entity, x, y, radius, damage float32) {
+	minX, minY := toGrid(x-radius, y-radius)
+	maxX, maxY := toGrid(x+radius, y+radius)
+	for gx := minX; gx <= maxX; gx++ {
+		for gy := minY; gy <= maxY; gy++ {
+			tile := w.tileAtGrid(gx, gy)
+			if tile == nil {
+				continue
+			}
+			dist := math.Hypot(float64(tile.x+TILEWIDTH/2-x), float64(tile.y+TILEWIDTH/2-y))
+			if dist < float64(radius) {
+				tile.Damage(damage * (1 - float32(dist)/radius))
+			}
+		}
 	}
+	w.healths.Each(func(id EntityID, h *Health) {
+		e, _ := w.entities.Get(id)
+		if !w.gunsCanHurt(source, e) {
+			return
+		}
+		cx, cy := e.Center()
+		dist := math.Hypot(float64(cx-x), float64(cy-y))
+		if dist < float64(radius) {
+			falloff := 1 - float32(dist)/radius
+			e.TakeDamage(source, damage*falloff, e.knockbackFrom(x, y, EXPLOSIONKNOCKBACK*falloff))
+		}
+	})
 }
 
 // Levels, Biomes, and TileChunks handle world generation
@@ -298,138 +434,3 @@ func NewWorldDataLoader() *WorldDataLoader {
 func (wdl *WorldDataLoader) GetTile(id uint32) *Tile {
 	return wdl.tiles[id]
 }
-
-// Starts with entrance, ends with exit. Collection of biomes
-type Level struct {
-	world  *World
-	perlin *perlin.Perlin
-	// Width of world in array coordinates
-	worldWidth uint32
-	// In array coordinates, start and end of visible world. Does not wrap
-	worldXStart uint32
-	worldXEnd   uint32
-	// In array coordinates, the most recenty generated tile. Does not wrap
-	worldXGen uint32
-	// Ring array for biomes
-	biomes      []Biome
-	curBiomeIdx int
-	biomeData   common.BiomeDataJson
-}
-
-func NewLevel(world *World, worldWidth uint32) *Level {
-	l := Level{
-		world:       world,
-		worldWidth:  worldWidth,
-		perlin:      perlin.NewPerlin(2, 2, 3, rand.Int63()),
-		curBiomeIdx: 0,
-		biomes:      make([]Biome, MAXWORLDGENBUFFERLEN/BIOMELENGTH+1),
-	}
-	common.LoadJSON("res/world/biomes.json", &l.biomeData)
-	l.biomes[0].biomeType = "start"
-	l.biomes[0].floorHeight = WORLDBUFFERHEIGHT / 2
-	l.biomes[0].BiomeJson = l.biomeData.Biomes["start"]
-	return &l
-}
-
-func (l *Level) initWorld() {
-	for x := uint32(0); x < WORLDBUFFERLEN; x++ {
-		for y := uint32(0); y < WORLDBUFFERHEIGHT; y++ {
-			l.world.worldTiles[y][x] = NewTile(0, float32(x*uint32(TILEWIDTH)), float32(y*uint32(TILEWIDTH)), l.world, nil)
-		}
-	}
-}
-
-func (l *Level) Update() {
-	if l.worldXEnd >= l.worldWidth {
-		l.world.canLeave = true
-		return
-	}
-	offX, _ := l.world.camera.GetRenderOffset()
-	l.worldXStart = uint32(-offX / TILEWIDTH)
-	l.worldXEnd = l.worldXStart + uint32(l.world.camera.screenWidth/TILEWIDTH)
-
-	// Update world generation
-	l.checkWorldUpdate()
-}
-
-func (l *Level) checkWorldUpdate() {
-	// If we are MINWORLDGENBUFFERLEN away from the generated section, should generate until we are MAXWORLDGENBUFFERLEN past generated section
-	if l.worldXStart+MINWORLDGENBUFFERLEN >= l.worldXGen {
-		// Range of generation: l.worldXGen -> l.worldXStart + MAXWORLDGENBUFFERLEN. Typically of length MAXWORLDGENBUFFERLEN - MINWORLDGENBUFFERLEN
-		for l.worldXStart+MAXWORLDGENBUFFERLEN >= l.worldXGen {
-			// Check for current biome, and if we need to make a new one
-			curBiome := &l.biomes[l.curBiomeIdx]
-			floorBase := curBiome.floorHeight
-			if curBiome.startX+BIOMELENGTH < l.worldXGen {
-				// This biome has finished being generated!
-				l.curBiomeIdx++
-				l.curBiomeIdx %= len(l.biomes)
-				// Generate new biome!
-				randIdx := int(rand.Uint32()) % len(curBiome.BiomeJson.NextTo)
-				newType := curBiome.BiomeJson.NextTo[randIdx]
-				newCur := &l.biomes[l.curBiomeIdx]
-				newCur.BiomeJson = l.biomeData.Biomes[newType]
-				newCur.startX = l.worldXGen
-				newCur.biomeType = newType
-				curBiome = newCur
-			}
-			// Generate terrain
-			arrX := l.toBufferIndex(l.worldXGen)
-			generateAmplitude := curBiome.GenAmplitude
-
-			rawY := l.perlin.Noise1D(float64(arrX) / (15.0 * curBiome.GenFrequency))
-			groundY := floorBase + uint32(rawY*float64(generateAmplitude))
-			curBiome.floorHeight = floorBase
-			if curBiome.startX+BIOMELENGTH < l.worldXGen+1 {
-				// This is the last square in the biome
-				curBiome.floorHeight = groundY
-			}
-
-			// TOD bO: Make these actual tile objects or sm
-			surfaceIm := l.world.gdl.GetSpriteImage(graphics.GrassTile)
-			subsurfaceIm := l.world.gdl.GetSpriteImage(graphics.DirtTile)
-			if curBiome.biomeType == "rocky" {
-				surfaceIm = l.world.gdl.GetSpriteImage(graphics.RockTile)
-				subsurfaceIm = l.world.gdl.GetSpriteImage(graphics.RockTile)
-			}
-
-			for y := uint32(0); y < WORLDBUFFERHEIGHT; y++ {
-				tile := l.world.worldTiles[y][arrX]
-				tile.x = float32(l.worldXGen) * TILEWIDTH
-				if y == groundY {
-					tile.im = surfaceIm
-					tile.isPassable = false
-				} else if y > groundY {
-					tile.im = subsurfaceIm
-					tile.isPassable = false
-				} else {
-					tile.im = nil
-					tile.isPassable = true
-				}
-			}
-			// Maybe zombie?
-			if rand.Intn(10) < 1 {
-				x := float32(l.worldXGen * uint32(TILEWIDTH))
-				z := NewBaseZombie(x, float32(groundY)*TILEWIDTH-TILEWIDTH, l.world)
-				l.world.zombieObjects = append(l.world.zombieObjects, z)
-				l.world.AddEntity(&z.Entity)
-			}
-			l.worldXGen++
-		}
-	}
-}
-
-func (l *Level) toBufferIndex(x uint32) uint32 {
-	return x % WORLDBUFFERLEN
-}
-
-// Variable length themes of tile chunks
-type Biome struct {
-	common.BiomeJson
-	// string key in BiomeDataJson
-	biomeType string
-	// Where did this biome start, in array coords
-	startX uint32
-	// At the end of this biome, what is the floor height
-	floorHeight uint32
-}
@@ -0,0 +1,170 @@
+package gameplay
+
+import (
+	"math"
+
+	"github.com/Jack-Craig/gogame/src/common"
+)
+
+// Kinds of tile a generation pass can place in a column
+type TileKind uint8
+
+const (
+	AirTile TileKind = iota
+	SurfaceTile
+	SubsurfaceTile
+	ClimbableTile
+)
+
+// One column of the world as it moves through the generation passes
+type GenColumn struct {
+	// In array coordinates, does not wrap
+	x       uint32
+	groundY uint32
+	tiles   [LEVELHEIGHT]TileKind
+	spawns  []SpawnEvent
+	// Came from a hand made chunk rather than the generation passes
+	authored bool
+}
+
+func (col *GenColumn) isSolid(y uint32) bool {
+	return col.tiles[y] == SurfaceTile || col.tiles[y] == SubsurfaceTile
+}
+
+// Generation passes run in order over every column, each one reading and editing what the last left behind.
+// Passes that span several columns keep their own state between calls
+type GenPass interface {
+	Apply(l *Level, b *Biome, col *GenColumn)
+}
+
+func DefaultGenPasses() []GenPass {
+	return []GenPass{
+		&HeightmapPass{},
+		&CavePass{},
+		&OverhangPass{},
+		&PlatformPass{},
+		&DecorationPass{},
+	}
+}
+
+// Solid below a perlin heightmap, air above
+type HeightmapPass struct{}
+
+func (p *HeightmapPass) Apply(l *Level, b *Biome, col *GenColumn) {
+	height := sampleHeight(l, &b.BiomeJson, b.baseHeight, col.x)
+	// Ease out of the previous biome's terrain instead of jumping straight to this one
+	if into := col.x - b.startX; b.hasPrev && into < BIOMEBLENDLENGTH {
+		t := float64(into+1) / float64(BIOMEBLENDLENGTH+1)
+		prevHeight := sampleHeight(l, &b.prev, b.prevBaseHeight, col.x)
+		height = prevHeight + (height-prevHeight)*t
+	}
+	y := int(math.Round(height))
+	if y < 1 {
+		y = 1
+	} else if y >= int(LEVELHEIGHT) {
+		y = int(LEVELHEIGHT) - 1
+	}
+	groundY := uint32(y)
+	if b.startX+b.length <= col.x+1 {
+		// This is the last square in the biome
+		b.floorHeight = groundY
+	}
+	col.groundY = groundY
+	col.tiles[groundY] = SurfaceTile
+	for y := groundY + 1; y < LEVELHEIGHT; y++ {
+		col.tiles[y] = SubsurfaceTile
+	}
+}
+
+// Ground height of biome settings bj at column x, around baseHeight
+func sampleHeight(l *Level, bj *common.BiomeJson, baseHeight float64, x uint32) float64 {
+	rawY := l.perlin.Noise1D(float64(x) / (15.0 * bj.GenFrequency))
+	return baseHeight + rawY*float64(bj.GenAmplitude)
+}
+
+// Carves 2D noise caves out of the ground, leaving the surface crust and bottom row intact
+type CavePass struct{}
+
+func (p *CavePass) Apply(l *Level, b *Biome, col *GenColumn) {
+	c := b.Generation.Caves
+	if !c.Enabled {
+		return
+	}
+	for y := col.groundY + c.MinDepth + 1; y < LEVELHEIGHT-1; y++ {
+		if l.perlin.Noise2D(float64(col.x)*c.Frequency, float64(y)*c.Frequency) > c.Threshold {
+			col.tiles[y] = AirTile
+		}
+	}
+}
+
+// Extends the lip of a falling cliff out over the drop
+type OverhangPass struct {
+	y         uint32
+	remaining uint32
+}
+
+func (p *OverhangPass) Apply(l *Level, b *Biome, col *GenColumn) {
+	o := b.Generation.Overhangs
+	if p.remaining == 0 && o.MaxLength > 0 && col.groundY >= l.lastGroundY+MINCLIMBABLECLIFF && l.rng.Float64() < o.Chance {
+		p.y = l.lastGroundY
+		p.remaining = 1 + uint32(l.rng.Intn(int(o.MaxLength)))
+	}
+	if p.remaining == 0 {
+		return
+	}
+	// Stop once the ground rises to meet the ledge
+	if p.y+1 >= col.groundY {
+		p.remaining = 0
+		return
+	}
+	col.tiles[p.y] = SurfaceTile
+	p.remaining--
+}
+
+// Floating one tile thick platforms above the ground
+type PlatformPass struct {
+	y         uint32
+	remaining uint32
+}
+
+func (p *PlatformPass) Apply(l *Level, b *Biome, col *GenColumn) {
+	pl := b.Generation.Platforms
+	if p.remaining == 0 {
+		if pl.MaxLength == 0 || pl.MaxHeight == 0 || l.rng.Float64() >= pl.Chance {
+			return
+		}
+		height := pl.MinHeight + uint32(l.rng.Intn(int(pl.MaxHeight-pl.MinHeight)+1))
+		if height >= col.groundY {
+			return
+		}
+		length := pl.MinLength + uint32(l.rng.Intn(int(pl.MaxLength-pl.MinLength)+1))
+		if length == 0 {
+			return
+		}
+		p.y = col.groundY - height
+		p.remaining = length
+	}
+	p.remaining--
+	// Leave room to walk underneath
+	if p.y+2 < col.groundY && col.tiles[p.y] == AirTile {
+		col.tiles[p.y] = SurfaceTile
+	}
+}
+
+// Non-colliding dressing, vines hang from the underside of platforms and overhangs down to the ground
+type DecorationPass struct{}
+
+func (p *DecorationPass) Apply(l *Level, b *Biome, col *GenColumn) {
+	d := b.Generation.Decoration
+	if d.VineChance <= 0 {
+		return
+	}
+	for y := uint32(1); y < col.groundY; y++ {
+		if !col.isSolid(y-1) || col.tiles[y] != AirTile || l.rng.Float64() >= d.VineChance {
+			continue
+		}
+		for ; y < col.groundY && col.tiles[y] == AirTile; y++ {
+			col.tiles[y] = ClimbableTile
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package gameplay
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPlatformsEnd(t *testing.T) {
+	var b Biome
+	pl := &b.Generation.Platforms
+	pl.Chance = 1
+	pl.MinLength, pl.MaxLength = 0, 3
+	pl.MinHeight, pl.MaxHeight = 3, 5
+	l := &Level{rng: rand.New(rand.NewSource(1))}
+	var p PlatformPass
+	for x := uint32(0); x < 1000; x++ {
+		col := GenColumn{x: x, groundY: LEVELHEIGHT / 2}
+		p.Apply(l, &b, &col)
+		if p.remaining > pl.MaxLength {
+			t.Fatalf("column %d: platform has %d columns left, longer than maxLength %d", x, p.remaining, pl.MaxLength)
+		}
+	}
+}
@@ -11,25 +11,26 @@ import (
 type Zombie struct {
 	Entity
 	zai ZombieAI
+	// Loot table dropped from when killed
+	lootTable string
+	// Told when this leaves the world, may be nil
+	spawnedBy Spawner
 }
 
 func NewZombie(x, y float32, world *World, ai ZombieAI) *Zombie {
-	z := &Zombie{}
+	z := &Zombie{Entity: *NewEntity(x, y, TILEWIDTH-1, TILEWIDTH-1, 0, world, world.gdl.GetSpriteImage(graphics.Bullet), true)}
 	ai.Init(z)
 	z.zai = ai
 	z.health = 100
-	z.GameObject = *NewGameObject(10, x, y, TILEWIDTH-1, TILEWIDTH-1, 0, world, world.gdl.GetSpriteImage(graphics.Bullet), true)
 	z.facingDir.X = 1
 	z.gravityMultiplier = 1
+	z.layer = ZombieLayer
+	z.mask = PlayerLayer | ZombieLayer | ProjectileLayer
 	z.walkAnimation = *world.gdl.GenerateAnimation(graphics.UserWalkFrame1, graphics.UserWalkFrame6)
 	z.idleAnimation = *world.gdl.GenerateAnimation(graphics.UserIdleFrame1, graphics.UserIdleFrame3)
 	return z
 }
 
-func (z *Zombie) Update() {
-	z.zai.Update()
-}
-
 type ZombieAI interface {
 	Init(z *Zombie)
 	Update()
@@ -42,38 +43,59 @@ type BaseZombieAI struct {
 	speed           float32
 	hearingDistance float32
 	attackDistance  float64
+	// Some zombies follow players up ladders and vines
+	canClimb bool
 
 	attackCooldown int64
 	lastAttack     int64
 }
 
 func NewBaseZombie(x, y float32, w *World) *Zombie {
-	return NewZombie(x, y, w, &BaseZombieAI{})
+	ai := &BaseZombieAI{}
+	z := NewZombie(x, y, w, ai)
+	z.lootTable = "walker"
+	if ai.canClimb {
+		z.lootTable = "climber"
+	}
+	return z
 }
 
 func (zai *BaseZombieAI) Init(z *Zombie) {
 	zai.z = z
-	zai.attackDistance = float64(TILEWIDTH / 2)
+	// Zombies and players block each other, so attack from touching distance
+	zai.attackDistance = float64(TILEWIDTH)
 	zai.attackCooldown = 1000
 	zai.speed = 1.5 + float32((rand.Int()%100))/75
 	zai.hearingDistance = 10*TILEWIDTH + float32((rand.Int() % (8 * int(TILEWIDTH))))
+	zai.canClimb = rand.Intn(3) == 0
 }
 
 func (zai *BaseZombieAI) Update() {
+	if zai.z.IsStunned() {
+		zai.z.vx = approach(zai.z.vx, 0, .5)
+		return
+	}
+	if zai.p != nil && (zai.p.shouldRemove || zai.p.isDowned) {
+		// Dead, downed or gone, find someone else
+		zai.p = nil
+	}
 	if zai.p == nil {
 		// Get nearest player
 		var nearestPlayer *Player
 		nearestDist := float64(-1)
-		for _, player := range zai.z.w.players {
+		zai.z.w.inputs.Each(func(id EntityID, player *Player) {
+			if player.shouldRemove || player.isDowned {
+				return
+			}
 			dist := math.Abs(float64(player.x-zai.z.x)) + math.Abs(float64(player.y-zai.z.y))
 			if dist > float64(zai.hearingDistance) {
-				continue
+				return
 			}
 			if nearestPlayer == nil || dist < nearestDist {
 				nearestDist = dist
 				nearestPlayer = player
 			}
-		}
+		})
 		zai.p = nearestPlayer
 	}
 	// No target. Maybe roam?
@@ -82,6 +104,14 @@ func (zai *BaseZombieAI) Update() {
 	}
 	dx := float64(zai.z.x - zai.p.x)
 	dy := math.Abs(float64(zai.z.y - zai.p.y))
+	if zai.canClimb {
+		zai.z.isClimbing = zai.z.IsOnClimbable() && dy > float64(TILEWIDTH/2)
+		if zai.z.isClimbing && zai.z.y > zai.p.y {
+			zai.z.vy = -CLIMBSPEED
+		} else if zai.z.isClimbing {
+			zai.z.vy = CLIMBSPEED
+		}
+	}
 	if dx < -zai.attackDistance {
 		zai.z.vx = zai.speed
 	} else if dx > zai.attackDistance {
@@ -95,7 +125,8 @@ func (zai *BaseZombieAI) Update() {
 				zai.lastAttack = timeNow
 			}
 			if timeNow > zai.lastAttack+zai.attackCooldown {
-				zai.p.health -= 25
+				zx, zy := zai.z.Center()
+				zai.p.TakeDamage(&zai.z.Entity, 25, zai.p.knockbackFrom(zx, zy, ZOMBIEKNOCKBACK))
 				zai.lastAttack = timeNow
 			}
 		}
@@ -1,6 +1,7 @@
 package graphics
 
 import (
+	"errors"
 	"fmt"
 	"image"
 	_ "image/png"
@@ -44,6 +45,14 @@ const (
 	UserWalkFrame4
 	UserWalkFrame5
 	UserWalkFrame6 // 21
+	LadderTile
+	VineTile
+	HealthPack
+	AmmoBox // 25
+	Coin
+	DamageBoost
+	RapidFire
+	Shield
 	Final
 )
 
@@ -55,15 +64,46 @@ type spriteData struct {
 }
 type mapData struct {
 	Frames map[string]spriteData
+	Meta   struct {
+		App, Version, Image, Format, Scale, SmartUpdate string
+		Size                                            struct{ W, H int }
+	}
+}
+
+func (md *mapData) Validate() error {
+	var errs []error
+	for cur := DirtTile; cur < Final; cur++ {
+		mapKey := fmt.Sprintf("%d.png", cur)
+		sd, ok := md.Frames[mapKey]
+		if !ok {
+			errs = append(errs, fmt.Errorf("frames.%s: missing, sprite %d has no frame", mapKey, cur))
+			continue
+		}
+		if sd.Frame.W <= 0 || sd.Frame.H <= 0 {
+			errs = append(errs, fmt.Errorf("frames.%s.frame: width and height must be greater than 0", mapKey))
+		}
+		if sd.Frame.X < 0 || sd.Frame.Y < 0 || sd.Frame.X+sd.Frame.W > md.Meta.Size.W || sd.Frame.Y+sd.Frame.H > md.Meta.Size.H {
+			errs = append(errs, fmt.Errorf("frames.%s.frame: outside of the %dx%d sheet", mapKey, md.Meta.Size.W, md.Meta.Size.H))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Loads and validates the sprite sheet map without touching the GPU
+func ValidateSpriteMap(filePath string) error {
+	var md mapData
+	return common.LoadJSON(filePath, &md)
 }
 
 // Loads buffered sprite sheet into memory
 // Loads sprit sheet map into memory
 // Serves requests from sprite_id to spritesheet coordinates
 type GraphicsDataLoader struct {
-	spriteSheet           *ebiten.Image
-	overlay               *ebiten.Image
-	spriteMap             map[SpriteID]*ebiten.Image
+	spriteSheet *ebiten.Image
+	overlay     *ebiten.Image
+	spriteMap   map[SpriteID]*ebiten.Image
+	// Sprite sheet pixel coordinates of each sprite's top left corner
+	spriteOrigins         map[image.Point]SpriteID
 	fontSmall, fontNormal font.Face
 }
 
@@ -84,13 +124,17 @@ func NewGraphicsDataLoader() *GraphicsDataLoader {
 
 	// Load spriteMap
 	var md mapData
-	common.LoadJSON("res/spritesheet.json", &md)
+	if err := common.LoadJSON("res/spritesheet.json", &md); err != nil {
+		log.Fatal(err)
+	}
 	gdl.spriteMap = make(map[SpriteID]*ebiten.Image)
+	gdl.spriteOrigins = make(map[image.Point]SpriteID)
 	for cur := DirtTile; cur < Final; cur++ {
 		mapKey := fmt.Sprintf("%d.png", cur)
 		sd := md.Frames[mapKey]
 		im := gdl.spriteSheet.SubImage(image.Rect(sd.Frame.X, sd.Frame.Y, sd.Frame.X+sd.Frame.W, sd.Frame.Y+sd.Frame.H)).(*ebiten.Image)
 		gdl.spriteMap[cur] = im
+		gdl.spriteOrigins[image.Pt(sd.Frame.X, sd.Frame.Y)] = cur
 	}
 	// Load overlay
 	overlayImageFile, err := os.Open("res/overlays/1.png")
@@ -152,6 +196,12 @@ func (gdl *GraphicsDataLoader) GetSpriteImage(spriteId SpriteID) *ebiten.Image {
 	return gdl.spriteMap[spriteId]
 }
 
+// Finds the sprite whose frame starts at x, y in the sprite sheet
+func (gdl *GraphicsDataLoader) GetSpriteAt(x, y int) (SpriteID, bool) {
+	id, ok := gdl.spriteOrigins[image.Pt(x, y)]
+	return id, ok
+}
+
 func (gdl *GraphicsDataLoader) GetFontSmall() *font.Face {
 	return &gdl.fontSmall
 }
@@ -0,0 +1,292 @@
+// Package tiled reads maps and tilesets saved by the Tiled editor (https://www.mapeditor.org) in the TMX and TSX formats
+package tiled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Tiled stores flip and rotation flags in the top bits of every tile gid
+	flipFlags uint32 = 0xF0000000
+)
+
+type Map struct {
+	Width           int           `xml:"width,attr"`
+	Height          int           `xml:"height,attr"`
+	TileWidth       int           `xml:"tilewidth,attr"`
+	TileHeight      int           `xml:"tileheight,attr"`
+	Infinite        bool          `xml:"infinite,attr"`
+	BackgroundColor string        `xml:"backgroundcolor,attr"`
+	Properties      Properties    `xml:"properties>property"`
+	Tilesets        []*Tileset    `xml:"tileset"`
+	Layers          []*Layer      `xml:"layer"`
+	ObjectGroups    []ObjectGroup `xml:"objectgroup"`
+}
+
+type Tileset struct {
+	FirstGID   uint32        `xml:"firstgid,attr"`
+	Source     string        `xml:"source,attr"`
+	Name       string        `xml:"name,attr"`
+	TileWidth  int           `xml:"tilewidth,attr"`
+	TileHeight int           `xml:"tileheight,attr"`
+	Spacing    int           `xml:"spacing,attr"`
+	Margin     int           `xml:"margin,attr"`
+	TileCount  int           `xml:"tilecount,attr"`
+	Columns    int           `xml:"columns,attr"`
+	Image      Image         `xml:"image"`
+	Tiles      []TilesetTile `xml:"tile"`
+}
+
+type Image struct {
+	Source string `xml:"source,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+}
+
+type TilesetTile struct {
+	ID         uint32     `xml:"id,attr"`
+	Properties Properties `xml:"properties>property"`
+}
+
+type Layer struct {
+	Name       string     `xml:"name,attr"`
+	Width      int        `xml:"width,attr"`
+	Height     int        `xml:"height,attr"`
+	Properties Properties `xml:"properties>property"`
+	Data       Data       `xml:"data"`
+	// Row major gids with the flip flags cleared, 0 is an empty cell
+	Tiles []uint32 `xml:"-"`
+}
+
+type Data struct {
+	Encoding    string `xml:"encoding,attr"`
+	Compression string `xml:"compression,attr"`
+	Raw         string `xml:",chardata"`
+}
+
+type ObjectGroup struct {
+	Name       string     `xml:"name,attr"`
+	Properties Properties `xml:"properties>property"`
+	Objects    []Object   `xml:"object"`
+}
+
+type Object struct {
+	ID     int     `xml:"id,attr"`
+	Name   string  `xml:"name,attr"`
+	Type   string  `xml:"type,attr"`
+	Class  string  `xml:"class,attr"`
+	X      float64 `xml:"x,attr"`
+	Y      float64 `xml:"y,attr"`
+	Width  float64 `xml:"width,attr"`
+	Height float64 `xml:"height,attr"`
+	// Tile objects are anchored at their bottom left corner
+	GID        uint32     `xml:"gid,attr"`
+	Properties Properties `xml:"properties>property"`
+}
+
+// Tiled 1.9 renamed an object's type to class, this accepts either
+func (o *Object) Kind() string {
+	if o.Class != "" {
+		return o.Class
+	}
+	return o.Type
+}
+
+type Property struct {
+	Name  string `xml:"name,attr"`
+	Type  string `xml:"type,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type Properties []Property
+
+func (ps Properties) get(name string) (string, bool) {
+	for _, p := range ps {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// Returns def when the property is missing or malformed
+func (ps Properties) Bool(name string, def bool) bool {
+	if v, ok := ps.get(name); ok {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return def
+}
+
+func (ps Properties) Float(name string, def float64) float64 {
+	if v, ok := ps.get(name); ok {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return def
+}
+
+func (ps Properties) Int(name string, def int) int {
+	if v, ok := ps.get(name); ok {
+		if i, err := strconv.Atoi(v); err == nil {
+			return i
+		}
+	}
+	return def
+}
+
+func (ps Properties) String(name string, def string) string {
+	if v, ok := ps.get(name); ok {
+		return v
+	}
+	return def
+}
+
+func (ps Properties) Has(name string) bool {
+	_, ok := ps.get(name)
+	return ok
+}
+
+// Loads a TMX map, along with any external TSX tilesets it references
+func Load(filePath string) (*Map, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var m Map
+	if err := xml.NewDecoder(f).Decode(&m); err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+	if m.Infinite {
+		return nil, fmt.Errorf("%s: infinite maps are not supported, resize the map in Tiled first", filePath)
+	}
+	for i, ts := range m.Tilesets {
+		if ts.Source == "" {
+			continue
+		}
+		external, err := LoadTileset(filepath.Join(filepath.Dir(filePath), ts.Source))
+		if err != nil {
+			return nil, fmt.Errorf("%s: tileset %d: %w", filePath, i, err)
+		}
+		external.FirstGID = ts.FirstGID
+		external.Source = ts.Source
+		m.Tilesets[i] = external
+	}
+	for _, layer := range m.Layers {
+		if err := layer.decode(); err != nil {
+			return nil, fmt.Errorf("%s: layer %q: %w", filePath, layer.Name, err)
+		}
+	}
+	return &m, nil
+}
+
+func LoadTileset(filePath string) (*Tileset, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var ts Tileset
+	if err := xml.NewDecoder(f).Decode(&ts); err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+	return &ts, nil
+}
+
+func (l *Layer) decode() error {
+	raw := strings.TrimSpace(l.Data.Raw)
+	switch l.Data.Encoding {
+	case "csv":
+		for _, field := range strings.Split(raw, ",") {
+			gid, err := strconv.ParseUint(strings.TrimSpace(field), 10, 32)
+			if err != nil {
+				return err
+			}
+			l.Tiles = append(l.Tiles, uint32(gid)&^flipFlags)
+		}
+	case "base64":
+		data, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil {
+			return err
+		}
+		var r io.Reader = bytes.NewReader(data)
+		switch l.Data.Compression {
+		case "":
+		case "zlib":
+			if r, err = zlib.NewReader(r); err != nil {
+				return err
+			}
+		case "gzip":
+			if r, err = gzip.NewReader(r); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported compression %q, use csv, zlib or gzip", l.Data.Compression)
+		}
+		gids := make([]uint32, l.Width*l.Height)
+		if err := binary.Read(r, binary.LittleEndian, gids); err != nil {
+			return err
+		}
+		for _, gid := range gids {
+			l.Tiles = append(l.Tiles, gid&^flipFlags)
+		}
+	default:
+		return fmt.Errorf("unsupported encoding %q, use csv or base64", l.Data.Encoding)
+	}
+	if len(l.Tiles) != l.Width*l.Height {
+		return fmt.Errorf("has %d tiles, expected %dx%d", len(l.Tiles), l.Width, l.Height)
+	}
+	return nil
+}
+
+// Returns the tile gid at column x, row y, 0 if empty
+func (l *Layer) At(x, y int) uint32 {
+	return l.Tiles[y*l.Width+x]
+}
+
+// Returns the tileset a gid belongs to and the tile's id within it
+func (m *Map) TilesetFor(gid uint32) (*Tileset, uint32, bool) {
+	var found *Tileset
+	for _, ts := range m.Tilesets {
+		if ts.FirstGID <= gid && (found == nil || ts.FirstGID > found.FirstGID) {
+			found = ts
+		}
+	}
+	if found == nil || gid == 0 {
+		return nil, 0, false
+	}
+	return found, gid - found.FirstGID, true
+}
+
+// Custom properties of tile id in ts, nil if it has none
+func (ts *Tileset) TileProperties(id uint32) Properties {
+	for _, t := range ts.Tiles {
+		if t.ID == id {
+			return t.Properties
+		}
+	}
+	return nil
+}
+
+// Pixel position of tile id within the tileset image
+func (ts *Tileset) TileOrigin(id uint32) (int, int) {
+	if ts.Columns == 0 {
+		return ts.Margin, ts.Margin
+	}
+	col, row := int(id)%ts.Columns, int(id)/ts.Columns
+	return ts.Margin + col*(ts.TileWidth+ts.Spacing), ts.Margin + row*(ts.TileHeight+ts.Spacing)
+}